@@ -1,4 +1,30 @@
-import { Team, TournamentType } from "@/api/tournament";
+import {
+  Availability,
+  Constraints,
+  Court,
+  Team,
+  TournamentType,
+} from "@/api/tournament";
+
+export interface FormatOptions {
+  groupsNumber?: number;
+  qualifiers?: number;
+  legs?: number;
+  daysBetweenRounds?: number;
+  consolation?: boolean;
+  thirdPlace?: boolean;
+  matchDuration?: number;
+  changeover?: number;
+  courts?: Court[];
+  availability?: Availability[];
+  showCourt?: number;
+  showCourtWeight?: number;
+  balanceRests?: boolean;
+  avoidPastPairings?: boolean;
+  pairing?: "Any" | "Mixed" | "SameGender";
+  fixedPairs?: Team[];
+  constraints?: Constraints;
+}
 
 export default function createTournament(
   bearerToken: string,
@@ -8,16 +34,51 @@ export default function createTournament(
   roundsNumber: number,
   availableCourts: number,
   teams: Team[],
+  options: FormatOptions = {},
 ): Promise<Response> {
   return fetch(
-    `/api/create-tournament?eventName=${eventName}&tournamentType=${tournamentType}&dateStart=${dateStart.toISOString()}&totalRounds=${roundsNumber}&availableCourts=${availableCourts}`,
+    `/api/create-tournament?eventName=${eventName}&tournamentType=${tournamentType}&dateStart=${dateStart.toISOString()}&totalRounds=${roundsNumber}&availableCourts=${availableCourts}&groupsNumber=${options.groupsNumber ?? 0}&qualifiers=${options.qualifiers ?? 0}&legs=${options.legs ?? 0}&daysBetweenRounds=${options.daysBetweenRounds ?? 0}&consolation=${options.consolation ?? false}&thirdPlace=${options.thirdPlace ?? false}&matchDuration=${options.matchDuration ?? 0}&changeover=${options.changeover ?? 0}&balanceRests=${options.balanceRests ?? false}&avoidPastPairings=${options.avoidPastPairings ?? false}&pairing=${options.pairing ?? "Any"}${options.showCourt ? `&showCourt=${options.showCourt}&showCourtWeight=${options.showCourtWeight ?? 2}` : ""}`,
     {
       method: "POST",
       headers: {
         Authorization: `Bearer ${bearerToken}`,
         "Content-Type": "application/json",
       },
-      body: JSON.stringify(teams),
+      body: JSON.stringify({
+        teams,
+        courts: options.courts ?? [],
+        availability: options.availability ?? [],
+        fixedPairs: options.fixedPairs ?? [],
+        constraints: options.constraints ?? {
+          neverPartners: [],
+          neverFace: [],
+          mustFace: [],
+        },
+      }),
     },
   );
 }
+
+// Why the server could not create the tournament, and what could be changed
+// so that it can.
+export interface Diagnosis {
+  reason: string;
+  message: string;
+  suggestions: string[] | null;
+}
+
+export async function describeFailure(response: Response): Promise<string> {
+  try {
+    const body = (await response.json()) as { diagnosis?: Diagnosis };
+    const diagnosis = body.diagnosis;
+    if (!diagnosis) {
+      return "Try again later";
+    }
+    const suggestions = diagnosis.suggestions ?? [];
+    return suggestions.length > 0
+      ? `${diagnosis.message}. Try to: ${suggestions.join(", or ")}.`
+      : diagnosis.message;
+  } catch {
+    return "Try again later";
+  }
+}
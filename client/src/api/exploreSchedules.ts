@@ -0,0 +1,32 @@
+import { TournamentType } from "@/api/tournament";
+
+// A combination of rounds and courts the server can generate a rodeo with.
+export interface ScheduleOption {
+  rounds: number;
+  courts: number;
+  playedRounds: number;
+  matchesPerParticipant: number;
+  matchesPerRound: number;
+  totalMatches: number;
+  restsPerParticipant: number;
+  restingPerRound: number;
+}
+
+export default async function exploreSchedules(
+  bearerToken: string,
+  tournamentType: TournamentType,
+  participants: number,
+): Promise<ScheduleOption[]> {
+  const params = new URLSearchParams({
+    tournamentType,
+    participants: participants.toString(),
+  });
+  const response = await fetch(`/api/explore-schedules?${params.toString()}`, {
+    headers: { Authorization: `Bearer ${bearerToken}` },
+  });
+  if (!response.ok) {
+    return [];
+  }
+  const body = (await response.json()) as { options: ScheduleOption[] | null };
+  return body.options ?? [];
+}
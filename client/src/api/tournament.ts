@@ -1,5 +1,13 @@
 export interface Person {
   id: string;
+  rating?: number;
+  gender?: number;
+}
+
+export interface Constraints {
+  neverPartners: [Person, Person][];
+  neverFace: [Person, Person][];
+  mustFace: [Person, Person][];
 }
 
 export interface Team {
@@ -22,27 +30,67 @@ export function genderToString(g: number): string {
 }
 
 export interface Match {
+  id: number;
   teamA: Team;
   teamB: Team;
   matchStatus: number;
   courtId: number;
+  scoreA: number;
+  scoreB: number;
+  stage: number;
 }
 
 export interface Matches {
   matches: Match[];
+  date: string;
 }
 
 export enum TournamentType {
   Rodeo = "Rodeo",
   SinglePlayerRodeo = "SinglePlayerRodeo",
+  Americano = "Americano",
+  Mexicano = "Mexicano",
+  Knockout = "Knockout",
+  GroupKnockout = "GroupKnockout",
+  RoundRobin = "RoundRobin",
+  Swiss = "Swiss",
+  KingOfTheCourt = "KingOfTheCourt",
+  Interclub = "Interclub",
+}
+
+export interface TimeWindow {
+  start: string;
+  end: string;
+}
+
+export interface Court {
+  id: number;
+  from?: string;
+  until?: string;
+  blackouts?: TimeWindow[];
+}
+
+export interface Availability {
+  person: Person;
+  firstRound?: number;
+  lastRound?: number;
+}
+
+export interface Club {
+  name: string;
+  squad: Team[];
 }
 
 export interface TournamentData {
+  id: number;
   name: string;
   date: string;
   teams: Team[];
   rounds: Matches[];
   tournamentType: TournamentType;
+  groups?: Team[][];
+  qualifiers?: number;
+  clubs?: Club[];
 }
 
 export interface Tournaments {
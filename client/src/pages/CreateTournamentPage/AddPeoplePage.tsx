@@ -7,12 +7,16 @@ import { TournamentSetupData } from "./CreateTournamentPage";
 import Box from "@mui/material/Box";
 import Button from "@mui/material/Button";
 import TextField from "@mui/material/TextField";
+import Select from "@mui/material/Select";
+import MenuItem from "@mui/material/MenuItem";
+import InputLabel from "@mui/material/InputLabel";
+import FormControl from "@mui/material/FormControl";
 import ListItemText from "@mui/material/ListItemText";
 import Snackbar from "@mui/material/Snackbar";
 import { Link } from "@/components/Link/Link.tsx";
 import { StatusDivider } from "@/components/StatusDivider";
 import { ActionList } from "@/components/ActionList";
-import createTournament from "@/api/createTournament";
+import createTournament, { describeFailure } from "@/api/createTournament";
 
 interface NotificationContent {
   title: string;
@@ -22,11 +26,23 @@ interface NotificationContent {
 
 export const peopleStore = {
   people: [] as Person[],
+  fixedPairs: [] as Team[],
   addPerson: (person: Person) => {
     peopleStore.people.push(person);
   },
+  addFixedPair: (person1: Person, person2: Person) => {
+    peopleStore.fixedPairs.push({
+      person1,
+      person2,
+      gender: person1.gender === person2.gender ? (person1.gender ?? 0) : 2,
+    });
+  },
   removePerson: (index: number) => {
-    peopleStore.people.splice(index, 1);
+    const [removed] = peopleStore.people.splice(index, 1);
+    peopleStore.fixedPairs = peopleStore.fixedPairs.filter(
+      (pair) =>
+        pair.person1.id !== removed.id && pair.person2.id !== removed.id,
+    );
   },
   getPeople: () => peopleStore.people,
 };
@@ -104,6 +120,7 @@ export const AddPeoplePage: FC = () => {
       config.roundsNumber,
       config.availableCourts,
       peopleToTeams(people),
+      { ...config, fixedPairs: peopleStore.fixedPairs },
     )
       .then((response) => {
         if (response.ok) {
@@ -113,7 +130,9 @@ export const AddPeoplePage: FC = () => {
             onClose: () => navigate("/"),
           });
         } else {
-          setOpen({ title: "Failed", description: "Try again later" });
+          describeFailure(response).then((description) =>
+            setOpen({ title: "Failed", description }),
+          );
         }
       })
       .catch((error) => {
@@ -191,11 +210,22 @@ export const AddPersonPage: FC = () => {
     const formData = new FormData(event.currentTarget);
 
     const person = formData.get("person") as string;
+    const gender = formData.get("gender") as string;
+    const partner = formData.get("partner") as string;
+    const partnerGender = formData.get("partnerGender") as string;
 
-    if (!person) return;
+    if (!person || gender === "") return;
 
-    const newPerson: Person = { id: person };
+    const newPerson: Person = { id: person, gender: parseInt(gender, 10) };
     peopleStore.addPerson(newPerson);
+    if (partner) {
+      const newPartner: Person = {
+        id: partner,
+        gender: partnerGender === "" ? undefined : parseInt(partnerGender, 10),
+      };
+      peopleStore.addPerson(newPartner);
+      peopleStore.addFixedPair(newPerson, newPartner);
+    }
 
     navigate("/create-tournament/add-players", {
       state: data,
@@ -210,6 +240,37 @@ export const AddPersonPage: FC = () => {
       sx={{ width: "100%", display: "flex", flexDirection: "column", gap: 2 }}
     >
       <TextField label="Player" name="person" variant="outlined" required />
+      <FormControl required>
+        <InputLabel id="gender-label">Gender</InputLabel>
+        <Select
+          labelId="gender-label"
+          label="Gender"
+          name="gender"
+          defaultValue=""
+        >
+          <MenuItem value={0}>Male</MenuItem>
+          <MenuItem value={1}>Female</MenuItem>
+          <MenuItem value={2}>Other</MenuItem>
+        </Select>
+      </FormControl>
+      <TextField
+        label="Always plays with (optional)"
+        name="partner"
+        variant="outlined"
+      />
+      <FormControl>
+        <InputLabel id="partner-gender-label">Partner gender</InputLabel>
+        <Select
+          labelId="partner-gender-label"
+          label="Partner gender"
+          name="partnerGender"
+          defaultValue=""
+        >
+          <MenuItem value={0}>Male</MenuItem>
+          <MenuItem value={1}>Female</MenuItem>
+          <MenuItem value={2}>Other</MenuItem>
+        </Select>
+      </FormControl>
       <Button type="submit" size="large" fullWidth variant="outlined">
         Add Player
       </Button>
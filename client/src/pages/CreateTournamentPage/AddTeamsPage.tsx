@@ -16,7 +16,7 @@ import Snackbar from "@mui/material/Snackbar";
 import { Link } from "@/components/Link/Link.tsx";
 import { StatusDivider } from "@/components/StatusDivider";
 import { ActionList } from "@/components/ActionList";
-import createTournament from "@/api/createTournament";
+import createTournament, { describeFailure } from "@/api/createTournament";
 
 interface NotificationContent {
   title: string;
@@ -86,6 +86,7 @@ export const AddTeamsPage: FC = () => {
       config.roundsNumber,
       config.availableCourts,
       teams,
+      config,
     )
       .then((response) => {
         if (response.ok) {
@@ -95,7 +96,9 @@ export const AddTeamsPage: FC = () => {
             onClose: () => navigate("/"),
           });
         } else {
-          setOpen({ title: "Failed", description: "Try again later" });
+          describeFailure(response).then((description) =>
+            setOpen({ title: "Failed", description }),
+          );
         }
       })
       .catch((error) => {
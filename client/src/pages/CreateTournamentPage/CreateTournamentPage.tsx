@@ -1,4 +1,4 @@
-import { useState, type FC } from "react";
+import { useEffect, useState, type FC } from "react";
 import { Form, Outlet, useNavigate } from "react-router-dom";
 import Box from "@mui/material/Box";
 import InputLabel from "@mui/material/InputLabel";
@@ -8,10 +8,14 @@ import Select from "@mui/material/Select";
 import MenuItem from "@mui/material/MenuItem";
 import Section from "@/components/Section";
 import FormControl from "@mui/material/FormControl";
+import FormControlLabel from "@mui/material/FormControlLabel";
+import Checkbox from "@mui/material/Checkbox";
 import { Page } from "@/components/Page";
 import { getMatchesPerTeam } from "./rodeoTournament";
 import { getMatchesPerPerson } from "./singlePlayerRodeoTournament";
 import { TournamentType } from "@/api/tournament";
+import { useAuth } from "@/components/AuthProvider";
+import exploreSchedules, { ScheduleOption } from "@/api/exploreSchedules";
 
 export const CreateTournamentPage: FC = () => {
   return (
@@ -30,6 +34,17 @@ export interface TournamentSetupData {
   tournamentDate: string;
   numberOfTeams: number;
   selectedTournament: TournamentType;
+  groupsNumber?: number;
+  qualifiers?: number;
+  legs?: number;
+  daysBetweenRounds?: number;
+  consolation?: boolean;
+  thirdPlace?: boolean;
+  matchDuration?: number;
+  changeover?: number;
+  balanceRests?: boolean;
+  avoidPastPairings?: boolean;
+  pairing?: "Any" | "Mixed" | "SameGender";
 }
 
 interface TournamentParamsProps {
@@ -51,6 +66,7 @@ const TournamentParams: React.FC<TournamentParamsProps> = ({
         label="Courts available"
         name="courtsAvailable"
         type="number"
+        value={formData.availableCourts || ""}
         onChange={(e) =>
           setFormData({
             ...formData,
@@ -63,6 +79,7 @@ const TournamentParams: React.FC<TournamentParamsProps> = ({
         label="Number of rounds"
         name="roundsNumber"
         type="number"
+        value={formData.roundsNumber || ""}
         onChange={(e) =>
           setFormData({
             ...formData,
@@ -88,6 +105,170 @@ const TournamentParams: React.FC<TournamentParamsProps> = ({
   );
 };
 
+interface ScheduleExplorerProps {
+  formData: TournamentSetupData;
+  setFormData: React.Dispatch<React.SetStateAction<TournamentSetupData>>;
+}
+
+// Lists the rounds and courts the tournament can be generated with, for the
+// number of participants, so that organisers do not have to guess them.
+const ScheduleExplorer: React.FC<ScheduleExplorerProps> = ({
+  formData,
+  setFormData,
+}) => {
+  const { bearerToken } = useAuth();
+  const [options, setOptions] = useState<ScheduleOption[]>([]);
+
+  useEffect(() => {
+    if (!formData.numberOfTeams) {
+      setOptions([]);
+      return;
+    }
+    let cancelled = false;
+    exploreSchedules(
+      bearerToken || "",
+      formData.selectedTournament,
+      formData.numberOfTeams,
+    )
+      .then((res) => {
+        if (!cancelled) {
+          setOptions(res);
+        }
+      })
+      .catch(() => setOptions([]));
+    return () => {
+      cancelled = true;
+    };
+  }, [bearerToken, formData.selectedTournament, formData.numberOfTeams]);
+
+  if (options.length === 0) {
+    return null;
+  }
+  return (
+    <FormControl>
+      <InputLabel id="schedule-option-label">Feasible schedules</InputLabel>
+      <Select
+        labelId="schedule-option-label"
+        label="Feasible schedules"
+        value={
+          options.some(
+            (o) =>
+              o.rounds === formData.roundsNumber &&
+              o.courts === formData.availableCourts,
+          )
+            ? `${formData.roundsNumber}-${formData.availableCourts}`
+            : ""
+        }
+        onChange={(e) => {
+          const [rounds, courts] = (e.target.value as string)
+            .split("-")
+            .map((v) => parseInt(v, 10));
+          setFormData({
+            ...formData,
+            roundsNumber: rounds,
+            availableCourts: courts,
+          });
+        }}
+      >
+        {options.map((o) => (
+          <MenuItem
+            key={`${o.rounds}-${o.courts}`}
+            value={`${o.rounds}-${o.courts}`}
+          >
+            {`${o.rounds} rounds, ${o.courts} courts: ${o.matchesPerParticipant} matches and ${o.restsPerParticipant} rests each, ${o.matchesPerRound} matches per round`}
+          </MenuItem>
+        ))}
+      </Select>
+    </FormControl>
+  );
+};
+
+interface EliminationParamsProps {
+  formData: TournamentSetupData;
+  setFormData: React.Dispatch<React.SetStateAction<TournamentSetupData>>;
+}
+
+const EliminationParams: React.FC<EliminationParamsProps> = ({
+  formData,
+  setFormData,
+}) => {
+  return (
+    <>
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={formData.consolation ?? false}
+            onChange={(e) =>
+              setFormData({ ...formData, consolation: e.target.checked })
+            }
+          />
+        }
+        label="Consolation bracket for first match losers"
+      />
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={formData.thirdPlace ?? false}
+            onChange={(e) =>
+              setFormData({ ...formData, thirdPlace: e.target.checked })
+            }
+          />
+        }
+        label="Third place match"
+      />
+    </>
+  );
+};
+
+interface ScheduleParamsProps {
+  formData: TournamentSetupData;
+  setFormData: React.Dispatch<React.SetStateAction<TournamentSetupData>>;
+}
+
+const ScheduleParams: React.FC<ScheduleParamsProps> = ({
+  formData,
+  setFormData,
+}) => {
+  return (
+    <>
+      <TextField
+        label="Match duration (minutes)"
+        name="matchDuration"
+        type="number"
+        helperText="Leave empty to print the rounds without start times."
+        onChange={(e) =>
+          setFormData({
+            ...formData,
+            matchDuration: parseInt(e.target.value, 10),
+          })
+        }
+      />
+      <TextField
+        label="Changeover (minutes)"
+        name="changeover"
+        type="number"
+        onChange={(e) =>
+          setFormData({
+            ...formData,
+            changeover: parseInt(e.target.value, 10),
+          })
+        }
+      />
+      <FormControlLabel
+        control={
+          <Checkbox
+            checked={formData.avoidPastPairings ?? false}
+            onChange={(e) =>
+              setFormData({ ...formData, avoidPastPairings: e.target.checked })
+            }
+          />
+        }
+        label="Avoid pairings of past tournaments"
+      />
+    </>
+  );
+};
+
 export const ChooseTournamentType: FC = () => {
   const [formData, setFormData] = useState<TournamentSetupData>({
     tournamentName: "",
@@ -96,6 +277,7 @@ export const ChooseTournamentType: FC = () => {
     tournamentDate: "",
     numberOfTeams: 0,
     selectedTournament: TournamentType.Rodeo,
+    legs: 1,
   });
   const navigate = useNavigate();
 
@@ -126,6 +308,19 @@ export const ChooseTournamentType: FC = () => {
               }}
               quantityDescription="Number of teams"
             />
+            <ScheduleExplorer formData={formData} setFormData={setFormData} />
+            <ScheduleParams formData={formData} setFormData={setFormData} />
+            <FormControlLabel
+              control={
+                <Checkbox
+                  checked={formData.balanceRests ?? false}
+                  onChange={(e) =>
+                    setFormData({ ...formData, balanceRests: e.target.checked })
+                  }
+                />
+              }
+              label="Avoid teams resting twice in a row"
+            />
             <Button
               type="button"
               variant="contained"
@@ -140,7 +335,9 @@ export const ChooseTournamentType: FC = () => {
         );
       }
 
-      case TournamentType.SinglePlayerRodeo: {
+      case TournamentType.SinglePlayerRodeo:
+      case TournamentType.Americano:
+      case TournamentType.Mexicano: {
         return (
           <>
             <TournamentParams
@@ -161,6 +358,34 @@ export const ChooseTournamentType: FC = () => {
               }}
               quantityDescription="Number of people"
             />
+            {formData.selectedTournament ===
+              TournamentType.SinglePlayerRodeo && (
+              <>
+                <ScheduleExplorer
+                  formData={formData}
+                  setFormData={setFormData}
+                />
+                <ScheduleParams formData={formData} setFormData={setFormData} />
+                <FormControl>
+                  <InputLabel id="pairing-label">Pairs</InputLabel>
+                  <Select
+                    labelId="pairing-label"
+                    label="Pairs"
+                    value={formData.pairing ?? "Any"}
+                    onChange={(e) =>
+                      setFormData({
+                        ...formData,
+                        pairing: e.target.value as TournamentSetupData["pairing"],
+                      })
+                    }
+                  >
+                    <MenuItem value="Any">Any</MenuItem>
+                    <MenuItem value="Mixed">Mixed</MenuItem>
+                    <MenuItem value="SameGender">Same gender</MenuItem>
+                  </Select>
+                </FormControl>
+              </>
+            )}
             <Button
               type="button"
               variant="contained"
@@ -174,6 +399,168 @@ export const ChooseTournamentType: FC = () => {
           </>
         );
       }
+      case TournamentType.Swiss:
+      case TournamentType.KingOfTheCourt:
+      case TournamentType.Knockout: {
+        return (
+          <>
+            <TournamentParams
+              formData={formData}
+              setFormData={setFormData}
+              helperText={() => {
+                switch (formData.selectedTournament) {
+                  case TournamentType.Swiss:
+                    return "Teams are seeded in the order they are added, the next rounds are paired from the results.";
+                  case TournamentType.KingOfTheCourt:
+                    return "Two teams per court, the first two teams added start on court 1.";
+                  default:
+                    return "Teams are seeded in the order they are added, top seeds get the byes.";
+                }
+              }}
+              quantityDescription="Number of teams"
+            />
+            {formData.selectedTournament === TournamentType.Knockout && (
+              <EliminationParams formData={formData} setFormData={setFormData} />
+            )}
+            <Button
+              type="button"
+              variant="contained"
+              size="large"
+              fullWidth
+              onClick={handleNextStep("/create-tournament/add-teams")}
+              disabled={!formData.tournamentDate || !formData.numberOfTeams}
+            >
+              Next: Add teams
+            </Button>
+          </>
+        );
+      }
+      case TournamentType.GroupKnockout: {
+        return (
+          <>
+            <TournamentParams
+              formData={formData}
+              setFormData={setFormData}
+              helperText={() =>
+                "Teams are seeded in the order they are added and spread over the groups."
+              }
+              quantityDescription="Number of teams"
+            />
+            <TextField
+              label="Number of groups"
+              name="groupsNumber"
+              type="number"
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  groupsNumber: parseInt(e.target.value, 10),
+                })
+              }
+              required
+            />
+            <TextField
+              label="Qualified teams per group"
+              name="qualifiers"
+              type="number"
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  qualifiers: parseInt(e.target.value, 10),
+                })
+              }
+              required
+            />
+            <EliminationParams formData={formData} setFormData={setFormData} />
+            <Button
+              type="button"
+              variant="contained"
+              size="large"
+              fullWidth
+              onClick={handleNextStep("/create-tournament/add-teams")}
+              disabled={
+                !formData.tournamentDate ||
+                !formData.numberOfTeams ||
+                !formData.groupsNumber ||
+                !formData.qualifiers
+              }
+            >
+              Next: Add teams
+            </Button>
+          </>
+        );
+      }
+      case TournamentType.RoundRobin: {
+        return (
+          <>
+            <TextField
+              label="Courts available"
+              name="courtsAvailable"
+              type="number"
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  availableCourts: parseInt(e.target.value, 10),
+                })
+              }
+              required
+            />
+            <TextField
+              label="Number of teams"
+              name="numberOfTeams"
+              type="number"
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  numberOfTeams: parseInt(e.target.value, 10),
+                })
+              }
+              required
+            />
+            <FormControl>
+              <InputLabel id="legs-label">Legs</InputLabel>
+              <Select
+                labelId="legs-label"
+                label="Legs"
+                name="legs"
+                value={formData.legs ?? 1}
+                onChange={(e) =>
+                  setFormData({ ...formData, legs: Number(e.target.value) })
+                }
+              >
+                <MenuItem value={1}>Single round robin</MenuItem>
+                <MenuItem value={2}>Double round robin</MenuItem>
+              </Select>
+            </FormControl>
+            <TextField
+              label="Days between match days"
+              name="daysBetweenRounds"
+              type="number"
+              helperText="The first match day is the tournament date."
+              onChange={(e) =>
+                setFormData({
+                  ...formData,
+                  daysBetweenRounds: parseInt(e.target.value, 10),
+                })
+              }
+              required
+            />
+            <Button
+              type="button"
+              variant="contained"
+              size="large"
+              fullWidth
+              onClick={handleNextStep("/create-tournament/add-teams")}
+              disabled={
+                !formData.tournamentDate ||
+                !formData.numberOfTeams ||
+                !formData.daysBetweenRounds
+              }
+            >
+              Next: Add teams
+            </Button>
+          </>
+        );
+      }
       default:
         return "Tournament type not supported";
     }
@@ -218,6 +605,17 @@ export const ChooseTournamentType: FC = () => {
             <MenuItem value={TournamentType.SinglePlayerRodeo}>
               Single Player Rodeo
             </MenuItem>
+            <MenuItem value={TournamentType.Americano}>Americano</MenuItem>
+            <MenuItem value={TournamentType.Mexicano}>Mexicano</MenuItem>
+            <MenuItem value={TournamentType.Knockout}>Knockout</MenuItem>
+            <MenuItem value={TournamentType.GroupKnockout}>
+              Groups and knockout
+            </MenuItem>
+            <MenuItem value={TournamentType.RoundRobin}>League</MenuItem>
+            <MenuItem value={TournamentType.Swiss}>Swiss</MenuItem>
+            <MenuItem value={TournamentType.KingOfTheCourt}>
+              King of the court
+            </MenuItem>
           </Select>
         </FormControl>
         <TextField
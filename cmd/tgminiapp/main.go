@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"embed"
+	"encoding/json"
+	"errors"
 	"io"
 	"io/fs"
 	"log"
@@ -39,6 +41,66 @@ type AuthRequest struct {
 	InitData string `json:"initDataRaw" binding:"required"`
 }
 
+type MatchResultRequest struct {
+	ScoreA int `json:"scoreA"`
+	ScoreB int `json:"scoreB"`
+}
+
+// CreateTournamentRequest is the payload of create-tournament. Interclub
+// leagues send Clubs instead of Teams. A plain array of teams is accepted as
+// well.
+type CreateTournamentRequest struct {
+	Teams  []tournament.Team  `json:"teams"`
+	Clubs  []tournament.Club  `json:"clubs"`
+	Courts []tournament.Court `json:"courts"`
+	// Availability limits the rounds of a rodeo some people can play in.
+	Availability []tournament.Availability `json:"availability"`
+	// FixedPairs are couples that always play together in single player
+	// rodeos.
+	FixedPairs []tournament.Team `json:"fixedPairs"`
+	// Constraints are the people that must never be partners, and that must
+	// never or must face each other, in rodeos.
+	Constraints tournament.Constraints `json:"constraints"`
+}
+
+// ChallengeRequest names the teams of a ladder challenge by their current
+// rank.
+type ChallengeRequest struct {
+	ChallengerRank int `json:"challengerRank"`
+	ChallengedRank int `json:"challengedRank"`
+}
+
+// Reads the show court from the showCourt and showCourtWeight query
+// parameters. Courts are not weighted when no show court is given.
+func parseCourtWeights(c *gin.Context) tournament.CourtWeights {
+	showCourt, err := strconv.Atoi(c.Query("showCourt"))
+	if err != nil {
+		return nil
+	}
+	weight, err := strconv.ParseFloat(c.Query("showCourtWeight"), 64)
+	if err != nil || weight <= 0 {
+		weight = 2
+	}
+	return tournament.CourtWeights{showCourt: weight}
+}
+
+// Writes the response for an error of a ladder operation, and tells whether
+// the operation succeeded.
+func handleLadderError(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, database.ErrLadderNotFound):
+		c.JSON(404, gin.H{"error": "ladder not found"})
+	case errors.Is(err, database.ErrInvalidChallenge):
+		c.JSON(400, gin.H{"error": err.Error()})
+	default:
+		log.Println("error while updating ladder: ", err)
+		c.JSON(500, gin.H{"error": "could not update ladder"})
+	}
+	return false
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -135,6 +197,30 @@ func main() {
 				"tournaments": tournaments})
 		})
 
+		protected.GET("/explore-schedules", func(c *gin.Context) {
+			participants, _ := strconv.Atoi(c.Query("participants"))
+			maxRounds, err := strconv.Atoi(c.Query("maxRounds"))
+			if err != nil || maxRounds <= 0 {
+				maxRounds = 10
+			}
+			maxCourts, err := strconv.Atoi(c.Query("maxCourts"))
+			if err != nil || maxCourts <= 0 {
+				maxCourts = 6
+			}
+
+			options, err := services.ExploreSchedules(
+				c.Query("tournamentType"),
+				participants,
+				maxRounds,
+				maxCourts,
+			)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"options": options})
+		})
+
 		protected.POST("/create-tournament", func(c *gin.Context) {
 			log.Println("create tournament handler called")
 			tournamentName := c.Query("eventName")
@@ -142,14 +228,40 @@ func main() {
 			dateStart, _ := time.Parse(time.RFC3339, c.Query("dateStart"))
 			totalRounds, _ := strconv.ParseInt(c.Query("totalRounds"), 10, 32)
 			availableCourts, _ := strconv.ParseInt(c.Query("availableCourts"), 10, 32)
+			groupsNumber, _ := strconv.ParseInt(c.Query("groupsNumber"), 10, 32)
+			qualifiers, _ := strconv.ParseInt(c.Query("qualifiers"), 10, 32)
+			legs, _ := strconv.ParseInt(c.Query("legs"), 10, 32)
+			daysBetweenRounds, _ := strconv.ParseInt(c.Query("daysBetweenRounds"), 10, 32)
+			consolation, _ := strconv.ParseBool(c.Query("consolation"))
+			thirdPlace, _ := strconv.ParseBool(c.Query("thirdPlace"))
+			rubbers, _ := strconv.ParseInt(c.Query("rubbers"), 10, 32)
+			matchDuration, _ := strconv.ParseInt(c.Query("matchDuration"), 10, 32)
+			changeover, _ := strconv.ParseInt(c.Query("changeover"), 10, 32)
+			balanceRests, _ := strconv.ParseBool(c.Query("balanceRests"))
+			avoidPastPairings, _ := strconv.ParseBool(c.Query("avoidPastPairings"))
+			pairing := tournament.PairingModeFromString(c.Query("pairing"))
 			userIdBlob, _ := c.Get("user_id")
 			userId := userIdBlob.(float64)
-			var teams []tournament.Team
-			if err := c.ShouldBindJSON(&teams); err != nil {
-				fmt.Print(err)
+
+			body, err := c.GetRawData()
+			if err != nil {
 				c.JSON(400, gin.H{"error": "Payload missing"})
 				return
 			}
+			var req CreateTournamentRequest
+			if err := json.Unmarshal(body, &req); err != nil {
+				if err := json.Unmarshal(body, &req.Teams); err != nil {
+					fmt.Print(err)
+					c.JSON(400, gin.H{"error": "Payload missing"})
+					return
+				}
+			}
+
+			// The teams of interclub leagues are the pairs of the squads.
+			teams := req.Teams
+			for _, club := range req.Clubs {
+				teams = append(teams, club.Squad...)
+			}
 
 			log.Printf(
 				"creating tournament %s, at date %s, with %v teams",
@@ -158,26 +270,434 @@ func main() {
 				len(teams),
 			)
 
-			tournament := services.CreateTournament(
+			var history *tournament.PairingHistory
+			if avoidPastPairings {
+				// Ids start from 1, the new tournament is not stored yet.
+				history, err = database.GetPairingHistory(ctx, conn, int64(userId), 0)
+				if err != nil {
+					log.Printf("error while retrieving past pairings: %v", err)
+					c.JSON(500, gin.H{"error": "could not retrieve past pairings"})
+					return
+				}
+			}
+
+			tournament, err := services.CreateTournament(
 				tournamentName,
 				tournamentType,
 				dateStart,
 				teams,
 				int(totalRounds),
 				int(availableCourts),
+				services.FormatOptions{
+					GroupsNumber:      int(groupsNumber),
+					Qualifiers:        int(qualifiers),
+					Legs:              int(legs),
+					DaysBetweenRounds: int(daysBetweenRounds),
+					Consolation:       consolation,
+					ThirdPlace:        thirdPlace,
+					Rubbers:           int(rubbers),
+					Clubs:             req.Clubs,
+					Courts:            req.Courts,
+					Availability:      req.Availability,
+					MatchDuration:     time.Duration(matchDuration) * time.Minute,
+					Changeover:        time.Duration(changeover) * time.Minute,
+					CourtWeights:      parseCourtWeights(c),
+					BalanceRests:      balanceRests,
+					History:           history,
+					Pairing:           pairing,
+					FixedPairs:        req.FixedPairs,
+					Constraints:       req.Constraints,
+				},
+			)
+
+			if err != nil {
+				c.JSON(400, gin.H{
+					"error":     "could not create tournament",
+					"diagnosis": services.Diagnosis(err),
+				})
+				return
+			}
+
+			err = database.CreateTournament(ctx, conn, int64(userId), tournament)
+			if err != nil {
+				log.Println("error while saving tournament: ", err)
+				c.JSON(500, gin.H{"error": "could not save tournament"})
+				return
+			}
+			c.Status(200)
+		})
+
+		protected.POST("/tournament/:id/match/:matchId/result", func(c *gin.Context) {
+			tournamentId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid tournament id"})
+				return
+			}
+			matchId, err := strconv.ParseInt(c.Param("matchId"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid match id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+			var req MatchResultRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				fmt.Print(err)
+				c.JSON(400, gin.H{"error": "Payload missing"})
+				return
+			}
+
+			err = database.RecordMatchResult(
+				ctx,
+				conn,
+				int64(userId),
+				tournamentId,
+				matchId,
+				req.ScoreA,
+				req.ScoreB,
 			)
+			if errors.Is(err, database.ErrTournamentNotFound) {
+				c.JSON(404, gin.H{"error": "match not found"})
+				return
+			}
+			if errors.Is(err, database.ErrDrawNotAllowed) {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				log.Println("error while recording result: ", err)
+				c.JSON(500, gin.H{"error": "could not record result"})
+				return
+			}
+			c.Status(200)
+		})
+
+		protected.POST("/tournament/:id/next-round", func(c *gin.Context) {
+			tournamentId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid tournament id"})
+				return
+			}
+			availableCourts, _ := strconv.ParseInt(c.Query("availableCourts"), 10, 32)
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			data, err := database.GetTournamentById(ctx, conn, int64(userId), tournamentId)
+			if err != nil {
+				log.Printf("error while retrieving tournament: %v", err)
+				c.JSON(404, gin.H{"error": "tournament not found"})
+				return
+			}
+
+			round, err := services.MakeNextRound(data.ToTournament(), int(availableCourts), parseCourtWeights(c))
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			err = database.AddRound(ctx, conn, int64(userId), tournamentId, len(data.Rounds), round)
+			if errors.Is(err, database.ErrRoundsChanged) {
+				c.JSON(409, gin.H{"error": "the tournament changed, reload it and try again"})
+				return
+			}
+			if err != nil {
+				log.Println("error while saving round: ", err)
+				c.JSON(500, gin.H{"error": "could not save round"})
+				return
+			}
+			c.JSON(200, round)
+		})
+
+		protected.POST("/tournament/:id/tie-break", func(c *gin.Context) {
+			tournamentId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid tournament id"})
+				return
+			}
+			availableCourts, _ := strconv.ParseInt(c.Query("availableCourts"), 10, 32)
+			var positions []int
+			if c.Query("positions") != "" {
+				for _, p := range strings.Split(c.Query("positions"), ",") {
+					position, err := strconv.Atoi(strings.TrimSpace(p))
+					if err != nil {
+						c.JSON(400, gin.H{"error": "invalid positions"})
+						return
+					}
+					positions = append(positions, position)
+				}
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			data, err := database.GetTournamentById(ctx, conn, int64(userId), tournamentId)
+			if err != nil {
+				log.Printf("error while retrieving tournament: %v", err)
+				c.JSON(404, gin.H{"error": "tournament not found"})
+				return
+			}
+
+			rounds, err := services.MakeTieBreak(
+				data.ToTournament(),
+				int(availableCourts),
+				positions,
+				parseCourtWeights(c),
+			)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			err = database.AddRounds(ctx, conn, int64(userId), tournamentId, len(data.Rounds), rounds)
+			if errors.Is(err, database.ErrRoundsChanged) {
+				c.JSON(409, gin.H{"error": "the tournament changed, reload it and try again"})
+				return
+			}
+			if err != nil {
+				log.Println("error while saving tie-break: ", err)
+				c.JSON(500, gin.H{"error": "could not save tie-break"})
+				return
+			}
+			c.JSON(200, rounds)
+		})
+
+		// Regenerates the rounds from fromRound on for the teams in the
+		// body, e.g. when a team withdraws or joins late.
+		protected.POST("/tournament/:id/reschedule", func(c *gin.Context) {
+			tournamentId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid tournament id"})
+				return
+			}
+			fromRound, err := strconv.ParseInt(c.Query("fromRound"), 10, 32)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid fromRound"})
+				return
+			}
+			availableCourts, _ := strconv.ParseInt(c.Query("availableCourts"), 10, 32)
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+			var teams []tournament.Team
+			if err := c.ShouldBindJSON(&teams); err != nil {
+				c.JSON(400, gin.H{"error": "Payload missing"})
+				return
+			}
+
+			data, err := database.GetTournamentById(ctx, conn, int64(userId), tournamentId)
+			if err != nil {
+				log.Printf("error while retrieving tournament: %v", err)
+				c.JSON(404, gin.H{"error": "tournament not found"})
+				return
+			}
 
-			if tournament != nil {
-				err = database.CreateTournament(ctx, conn, int64(userId), tournament)
+			var history *tournament.PairingHistory
+			if data.RodeoOptions.AvoidPastPairings {
+				history, err = database.GetPairingHistory(ctx, conn, int64(userId), tournamentId)
 				if err != nil {
-					log.Println("error while saving tournament: ", err)
-					c.JSON(500, gin.H{"error": "could not save tournament"})
+					log.Printf("error while retrieving past pairings: %v", err)
+					c.JSON(500, gin.H{"error": "could not retrieve past pairings"})
 					return
 				}
-				c.Status(200)
-			} else {
-				c.JSON(400, gin.H{"error": "could not create tournament"})
 			}
+
+			rescheduled, err := services.Reschedule(
+				data.ToTournament(),
+				int(fromRound),
+				teams,
+				int(availableCourts),
+				parseCourtWeights(c),
+				history,
+			)
+			if err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			err = database.RescheduleTournament(ctx, conn, int64(userId), tournamentId, int(fromRound), rescheduled)
+			if err != nil {
+				log.Println("error while saving rescheduled tournament: ", err)
+				c.JSON(500, gin.H{"error": "could not save rescheduled tournament"})
+				return
+			}
+			c.JSON(200, rescheduled.GetRounds())
+		})
+
+		protected.POST("/ladder", func(c *gin.Context) {
+			ladderName := c.Query("eventName")
+			dateStart, _ := time.Parse(time.RFC3339, c.Query("dateStart"))
+			maxChallengeDistance, _ := strconv.ParseInt(c.Query("maxChallengeDistance"), 10, 32)
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+			var teams []tournament.Team
+			if err := c.ShouldBindJSON(&teams); err != nil {
+				fmt.Print(err)
+				c.JSON(400, gin.H{"error": "Payload missing"})
+				return
+			}
+
+			// Teams are sent sorted by their starting rank.
+			ladder := tournament.NewLadder(ladderName, dateStart, teams, int(maxChallengeDistance))
+			if err := ladder.Validate(); err != nil {
+				c.JSON(400, gin.H{"error": err.Error()})
+				return
+			}
+
+			ladderId, err := database.CreateLadder(ctx, conn, int64(userId), ladder)
+			if err != nil {
+				log.Println("error while saving ladder: ", err)
+				c.JSON(500, gin.H{"error": "could not save ladder"})
+				return
+			}
+			c.JSON(200, gin.H{"id": ladderId})
+		})
+
+		protected.GET("/ladder/:id", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			ladder, err := database.GetLadderById(ctx, conn, int64(userId), ladderId)
+			if errors.Is(err, database.ErrLadderNotFound) {
+				c.JSON(404, gin.H{"error": "ladder not found"})
+				return
+			}
+			if err != nil {
+				log.Printf("error while retrieving ladder: %v", err)
+				c.JSON(500, gin.H{"error": "could not retrieve ladder"})
+				return
+			}
+			c.JSON(200, ladder)
+		})
+
+		protected.POST("/ladder/:id/challenge", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+			var req ChallengeRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				fmt.Print(err)
+				c.JSON(400, gin.H{"error": "Payload missing"})
+				return
+			}
+
+			challenge, err := database.IssueChallenge(
+				ctx,
+				conn,
+				int64(userId),
+				ladderId,
+				req.ChallengerRank,
+				req.ChallengedRank,
+			)
+			if !handleLadderError(c, err) {
+				return
+			}
+			c.JSON(200, challenge)
+		})
+
+		protected.POST("/ladder/:id/challenge/:challengeId/accept", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			challengeId, err := strconv.ParseInt(c.Param("challengeId"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid challenge id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			err = database.AcceptChallenge(ctx, conn, int64(userId), ladderId, challengeId)
+			if !handleLadderError(c, err) {
+				return
+			}
+			c.Status(200)
+		})
+
+		protected.POST("/ladder/:id/challenge/:challengeId/decline", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			challengeId, err := strconv.ParseInt(c.Param("challengeId"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid challenge id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			err = database.DeclineChallenge(ctx, conn, int64(userId), ladderId, challengeId)
+			if !handleLadderError(c, err) {
+				return
+			}
+			c.Status(200)
+		})
+
+		protected.POST("/ladder/:id/challenge/:challengeId/withdraw", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			challengeId, err := strconv.ParseInt(c.Param("challengeId"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid challenge id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+
+			err = database.WithdrawChallenge(ctx, conn, int64(userId), ladderId, challengeId)
+			if !handleLadderError(c, err) {
+				return
+			}
+			c.Status(200)
+		})
+
+		// ScoreA is the score of the challenger, ScoreB of the challenged team.
+		protected.POST("/ladder/:id/challenge/:challengeId/result", func(c *gin.Context) {
+			ladderId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid ladder id"})
+				return
+			}
+			challengeId, err := strconv.ParseInt(c.Param("challengeId"), 10, 64)
+			if err != nil {
+				c.JSON(400, gin.H{"error": "invalid challenge id"})
+				return
+			}
+			userIdBlob, _ := c.Get("user_id")
+			userId := userIdBlob.(float64)
+			var req MatchResultRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				fmt.Print(err)
+				c.JSON(400, gin.H{"error": "Payload missing"})
+				return
+			}
+
+			err = database.ReportChallengeResult(
+				ctx,
+				conn,
+				int64(userId),
+				ladderId,
+				challengeId,
+				req.ScoreA,
+				req.ScoreB,
+			)
+			if !handleLadderError(c, err) {
+				return
+			}
+			c.Status(200)
 		})
 
 		protected.POST("/tournament/generate-link", func(c *gin.Context) {
@@ -217,7 +737,7 @@ func main() {
 		userData, _ := whitelistedIDs.GetLogoPath(user)
 		pdfPath, err := tournamentPdfGenerator.CreatePdfTournament(
 			services.FromTournamentDataToTemplateData(tournament),
-			services.Rodeo,
+			services.GetTemplateType(tournament.TournamentType),
 			fmt.Sprint(tournament.Date.Format("2006-01-02"), "_", tournament.Name, "_", token),
 			userData,
 		)
@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"time"
@@ -19,57 +20,111 @@ func queryInsertTeam(ctx context.Context, tx pgx.Tx, team1 tournament.Team) (int
 				INSERT INTO person (name)
     		SELECT DISTINCT name 
 				FROM (VALUES ($1::TEXT), ($2::TEXT)) AS input(name)
+				WHERE name <> ''
     		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
     		RETURNING id, name
     )
-    INSERT INTO team (person1_id, person2_id)
+    INSERT INTO team (person1_id, person2_id, gender_id)
     SELECT 
         (SELECT id FROM upserted_people WHERE name = $1),
-        (SELECT id FROM upserted_people WHERE name = $2)
+        (SELECT id FROM upserted_people WHERE name = $2),
+        $3
     RETURNING id;`
 
 	person1 := team1.Person1.Id
 	person2 := team1.Person2.Id
+	// Genders are numbered from 1 in the database.
+	genderId := int(team1.TeamGender) + 1
 
 	var id int64
-	if err := tx.QueryRow(ctx, sql, person1, person2).Scan(&id); err != nil {
+	if err := tx.QueryRow(ctx, sql, person1, person2, genderId).Scan(&id); err != nil {
 		return -1, fmt.Errorf("error while inserting team: %w", err)
 	}
 
 	return id, nil
 }
 
+func queryRegisterTeam(
+	ctx context.Context,
+	tx pgx.Tx,
+	tournamentId, teamId int64,
+	seed, groupNumber int,
+	clubId *int64,
+) error {
+
+	const sql = `
+		INSERT INTO tournament_team (tournament_id, team_id, seed, group_number, club_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tournament_id, team_id) DO UPDATE SET seed = EXCLUDED.seed;`
+
+	if _, err := tx.Exec(ctx, sql, tournamentId, teamId, seed, groupNumber, clubId); err != nil {
+		return fmt.Errorf("error while registering team: %w", err)
+	}
+
+	return nil
+}
+
+func queryInsertClub(ctx context.Context, tx pgx.Tx, name string) (int64, error) {
+
+	const sql = `
+		INSERT INTO club (name)
+		VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id;`
+
+	var id int64
+	if err := tx.QueryRow(ctx, sql, name).Scan(&id); err != nil {
+		return -1, fmt.Errorf("error while inserting club: %w", err)
+	}
+
+	return id, nil
+}
+
 func queryCreateMatch(
 	ctx context.Context,
 	tx pgx.Tx,
 	round_number int,
+	roundDate *time.Time,
 	tournamentId, team1Id, team2Id int64,
-	court_number int,
+	match tournament.Match,
 ) error {
 
 	const sql = `
 		WITH
 
 		new_match AS (
-				INSERT INTO "match" (team1_id, team2_id, court_number)
+				INSERT INTO "match" (team1_id, team2_id, court_number, team1_score, team2_score, match_status, stage)
 				VALUES (
-						($1),	($2), ($3)
+						($1),	($2), ($3), ($6), ($7), ($8), ($9)
 				)
 				RETURNING id
 		)
 
-		INSERT INTO round_tournament (tournament_id, match_id, round_number)
+		INSERT INTO round_tournament (tournament_id, match_id, round_number, round_date)
 		VALUES (
 				($4),
 				(SELECT id FROM new_match),
-				($5)
+				($5),
+				($10)
 		)
 		RETURNING tournament_id;
 	`
 
 	var id int64
-	if err := tx.QueryRow(ctx, sql, team1Id, team2Id, court_number, tournamentId, round_number).
-		Scan(&id); err != nil {
+	if err := tx.QueryRow(
+		ctx,
+		sql,
+		team1Id,
+		team2Id,
+		match.CourtId,
+		tournamentId,
+		round_number,
+		match.ScoreA,
+		match.ScoreB,
+		int(match.MatchStatus),
+		int(match.Stage),
+		roundDate,
+	).Scan(&id); err != nil {
 		return fmt.Errorf("error while creating match: %w", err)
 	}
 
@@ -83,24 +138,113 @@ func queryCreateTournament(
 	tournamentName string,
 	tournamentDate time.Time,
 	tournamentType string,
+	qualifiers int,
+	elimination tournament.EliminationOptions,
+	rodeoOptions []byte,
 ) (int64, error) {
 
 	sql := `
-    INSERT INTO tournament (event_name, tournament_date, tournament_type_id, user_id)
-    VALUES ($1, $2, (SELECT id FROM tournament_type WHERE name = $3), $4)
+    INSERT INTO tournament (event_name, tournament_date, tournament_type_id, user_id, qualifiers,
+        consolation, third_place, rodeo_options)
+    VALUES ($1, $2, (SELECT id FROM tournament_type WHERE name = $3), $4, $5, $6, $7, $8)
     RETURNING id;`
 
 	log.Printf("tournament type %v", tournamentType)
 
 	var id int64
-	if err := tx.QueryRow(ctx, sql, tournamentName, tournamentDate, tournamentType, userId).
-		Scan(&id); err != nil {
+	if err := tx.QueryRow(
+		ctx,
+		sql,
+		tournamentName,
+		tournamentDate,
+		tournamentType,
+		userId,
+		qualifiers,
+		elimination.Consolation,
+		elimination.ThirdPlace,
+		rodeoOptions,
+	).Scan(&id); err != nil {
 		return -1, fmt.Errorf("error while creating tournament: %w", err)
 	}
 
 	return id, nil
 }
 
+// Inserts the matches of a round, together with the teams that were not
+// inserted yet. teamIds is updated with the newly inserted teams.
+func queryCreateRound(
+	ctx context.Context,
+	tx pgx.Tx,
+	tournamentId int64,
+	roundNumber int,
+	round tournament.Round,
+	teamIds map[tournament.TeamKey]int64,
+) error {
+
+	getTeamId := func(team tournament.Team) (int64, error) {
+		if id, ok := teamIds[team.Key()]; ok {
+			return id, nil
+		}
+		id, err := queryInsertTeam(ctx, tx, team)
+		if err != nil {
+			return -1, err
+		}
+		teamIds[team.Key()] = id
+		return id, nil
+	}
+
+	// Rounds without a date are stored with a NULL round_date.
+	var roundDate *time.Time
+	if !round.Date.IsZero() {
+		roundDate = &round.Date
+	}
+
+	for _, match := range round.Matches {
+		team1Id, err := getTeamId(*match.TeamA)
+		if err != nil {
+			return err
+		}
+		team2Id, err := getTeamId(*match.TeamB)
+		if err != nil {
+			return err
+		}
+
+		err = queryCreateMatch(
+			ctx,
+			tx,
+			roundNumber,
+			roundDate,
+			tournamentId,
+			team1Id,
+			team2Id,
+			match,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the options of a rodeo as stored with the tournament, nil for the
+// other formats.
+func marshalRodeoOptions(t tournament.Tournament) ([]byte, error) {
+	rodeo, ok := t.(*tournament.Rodeo)
+	if !ok {
+		return nil, nil
+	}
+	res, err := json.Marshal(rodeo.Options)
+	if err != nil {
+		return nil, fmt.Errorf("error while encoding rodeo options: %w", err)
+	}
+	return res, nil
+}
+
+type eliminationTournament interface {
+	GetEliminationOptions() tournament.EliminationOptions
+}
+
 func CreateTournament(
 	ctx context.Context,
 	conn *pgxpool.Pool,
@@ -125,40 +269,77 @@ func CreateTournament(
 		return fmt.Errorf("error converting tournament type to string: %w", err)
 	}
 
+	// Group numbers start from 1, 0 is for teams that are not part of a group.
+	qualifiers := 0
+	groupNumbers := make(map[tournament.TeamKey]int)
+	if gk, ok := t.(*tournament.GroupKnockout); ok {
+		qualifiers = gk.Qualifiers
+		for i, group := range gk.Groups {
+			for _, team := range group {
+				groupNumbers[team.Key()] = i + 1
+			}
+		}
+	}
+
+	// Teams of an interclub league are registered with their club.
+	clubIds := make(map[tournament.TeamKey]*int64)
+	if ic, ok := t.(*tournament.Interclub); ok {
+		for _, club := range ic.Clubs {
+			clubId, err := queryInsertClub(ctx, tx, club.Name)
+			if err != nil {
+				return err
+			}
+			for _, team := range club.Squad {
+				clubIds[team.Key()] = &clubId
+			}
+		}
+	}
+
+	var elimination tournament.EliminationOptions
+	if e, ok := t.(eliminationTournament); ok {
+		elimination = e.GetEliminationOptions()
+	}
+
+	rodeoOptions, err := marshalRodeoOptions(t)
+	if err != nil {
+		return err
+	}
+
 	log.Printf("tournament type to string is %v", tournamentType)
 	tournamentId, err := queryCreateTournament(ctx, tx, userId, t.GetName(), t.GetDateStart(),
 
-		tournamentType)
+		tournamentType, qualifiers, elimination, rodeoOptions)
 	if err != nil {
 		return err
 	}
 
-	teamIds := make(map[tournament.Team]int64)
-	for _, team := range t.GetTeams() {
+	teamIds := make(map[tournament.TeamKey]int64)
+	for seed, team := range t.GetTeams() {
+		if _, ok := teamIds[team.Key()]; ok {
+			continue
+		}
 		teamId, err := queryInsertTeam(ctx, tx, team)
 		if err != nil {
 			return err
 		}
-		teamIds[team] = teamId
-	}
-
-	rounds := t.GetRounds()
-	for roundIndex, round := range rounds {
-		for _, match := range round.Matches {
-			team1Id := teamIds[*match.TeamA]
-			team2Id := teamIds[*match.TeamB]
-			err := queryCreateMatch(
-				ctx,
-				tx,
-				roundIndex,
-				tournamentId,
-				team1Id,
-				team2Id,
-				match.CourtId,
-			)
-			if err != nil {
-				return err
-			}
+		teamIds[team.Key()] = teamId
+
+		if err := queryRegisterTeam(
+			ctx,
+			tx,
+			tournamentId,
+			teamId,
+			seed,
+			groupNumbers[team.Key()],
+			clubIds[team.Key()],
+		); err != nil {
+			return err
+		}
+	}
+
+	for roundIndex, round := range t.GetRounds() {
+		if err := queryCreateRound(ctx, tx, tournamentId, roundIndex, round, teamIds); err != nil {
+			return err
 		}
 	}
 	if err = tx.Commit(ctx); err != nil {
@@ -0,0 +1,395 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrLadderNotFound   = errors.New("ladder not found")
+	ErrInvalidChallenge = errors.New("invalid challenge")
+)
+
+type ladderRow struct {
+	LadderId             int64
+	EventName            string
+	StartDate            time.Time
+	MaxChallengeDistance int
+}
+
+type ladderTeam struct {
+	TeamId  int64
+	Person1 string
+	Person2 string
+	Gender  string
+}
+
+const teamsByLadderId = `
+SELECT team.id, p1.name, COALESCE(p2.name, ''), gender.name
+FROM ladder_team
+JOIN team ON ladder_team.team_id=team.id
+JOIN person p1 ON team.person1_id=p1.id
+LEFT JOIN person p2 ON team.person2_id=p2.id
+JOIN gender ON team.gender_id=gender.id
+WHERE ladder_team.ladder_id=$1
+ORDER BY ladder_team.rank
+`
+
+type challengeRow struct {
+	ChallengeId     int64
+	ChallengerId    int64
+	ChallengedId    int64
+	Status          int
+	ChallengerScore int
+	ChallengedScore int
+	IssuedAt        time.Time
+}
+
+const challengesByLadderId = `
+SELECT id, challenger_id, challenged_id, status, challenger_score, challenged_score, issued_at
+FROM challenge
+WHERE ladder_id=$1
+ORDER BY issued_at, id
+`
+
+func CreateLadder(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladder *tournament.Ladder,
+) (int64, error) {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("msg rolling back transaction: %v", err)
+		}
+	}()
+
+	const sql = `
+		INSERT INTO ladder (event_name, start_date, max_challenge_distance, user_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id;`
+
+	var ladderId int64
+	err = tx.QueryRow(
+		ctx,
+		sql,
+		ladder.Name,
+		ladder.DateStart,
+		ladder.MaxChallengeDistance,
+		userId,
+	).Scan(&ladderId)
+	if err != nil {
+		return -1, fmt.Errorf("error while inserting ladder: %w", err)
+	}
+
+	const sqlTeam = `INSERT INTO ladder_team (ladder_id, team_id, rank) VALUES ($1, $2, $3);`
+
+	for i, team := range ladder.Teams {
+		teamId, err := queryInsertTeam(ctx, tx, team)
+		if err != nil {
+			return -1, err
+		}
+		if _, err := tx.Exec(ctx, sqlTeam, ladderId, teamId, i+1); err != nil {
+			return -1, fmt.Errorf("error while registering team to ladder: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return -1, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return ladderId, nil
+}
+
+// Reads the ladder, locking it until the end of the transaction so that
+// concurrent challenges are validated against the same ranks. Also returns
+// the ids of its teams.
+func queryGetLadder(
+	ctx context.Context,
+	tx pgx.Tx,
+	userId int64,
+	ladderId int64,
+) (*tournament.Ladder, map[tournament.TeamKey]int64, error) {
+
+	const sql = `
+		SELECT id, event_name, start_date, max_challenge_distance
+		FROM ladder
+		WHERE id = $1 AND user_id = $2
+		FOR UPDATE;`
+
+	rows, err := tx.Query(ctx, sql, ladderId, userId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query error: %w", err)
+	}
+	row, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByPos[ladderRow])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, ErrLadderNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("scan error: %w", err)
+	}
+
+	rows, err = tx.Query(ctx, teamsByLadderId, ladderId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query error: %w", err)
+	}
+	teamRows, err := pgx.CollectRows(rows, pgx.RowToStructByPos[ladderTeam])
+	if err != nil {
+		return nil, nil, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	teamIds := make(map[tournament.TeamKey]int64)
+	teamsById := make(map[int64]tournament.Team)
+	teams := make([]tournament.Team, 0, len(teamRows))
+	for _, t := range teamRows {
+		team := tournament.Team{
+			Person1:    tournament.Person{Id: t.Person1},
+			Person2:    tournament.Person{Id: t.Person2},
+			TeamGender: tournament.GenderFromString(t.Gender),
+		}
+		teams = append(teams, team)
+		teamIds[team.Key()] = t.TeamId
+		teamsById[t.TeamId] = team
+	}
+
+	rows, err = tx.Query(ctx, challengesByLadderId, ladderId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("query error: %w", err)
+	}
+	challengeRows, err := pgx.CollectRows(rows, pgx.RowToStructByPos[challengeRow])
+	if err != nil {
+		return nil, nil, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	ladder := tournament.NewLadder(row.EventName, row.StartDate, teams, row.MaxChallengeDistance)
+	ladder.Id = row.LadderId
+	for _, c := range challengeRows {
+		ladder.Challenges = append(ladder.Challenges, tournament.Challenge{
+			Id:              c.ChallengeId,
+			Challenger:      teamsById[c.ChallengerId],
+			Challenged:      teamsById[c.ChallengedId],
+			Status:          tournament.ChallengeStatus(c.Status),
+			ScoreChallenger: c.ChallengerScore,
+			ScoreChallenged: c.ChallengedScore,
+			IssuedAt:        c.IssuedAt,
+		})
+	}
+
+	return ladder, teamIds, nil
+}
+
+// Runs update on the ladder within a transaction, the ladder is locked until
+// the transaction ends.
+func withLadder(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	update func(pgx.Tx, *tournament.Ladder, map[tournament.TeamKey]int64) error,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("msg rolling back transaction: %v", err)
+		}
+	}()
+
+	ladder, teamIds, err := queryGetLadder(ctx, tx, userId, ladderId)
+	if err != nil {
+		return err
+	}
+
+	if err := update(tx, ladder, teamIds); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func GetLadderById(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+) (*tournament.Ladder, error) {
+	var res *tournament.Ladder
+	err := withLadder(ctx, conn, userId, ladderId,
+		func(_ pgx.Tx, ladder *tournament.Ladder, _ map[tournament.TeamKey]int64) error {
+			res = ladder
+			return nil
+		},
+	)
+	return res, err
+}
+
+// IssueChallenge stores a challenge of the team ranked challengerRank to the
+// team ranked challengedRank. Errors of the ladder rules wrap
+// ErrInvalidChallenge.
+func IssueChallenge(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengerRank int,
+	challengedRank int,
+) (tournament.Challenge, error) {
+
+	const sql = `
+		INSERT INTO challenge (ladder_id, challenger_id, challenged_id, status, issued_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;`
+
+	var res tournament.Challenge
+	err := withLadder(ctx, conn, userId, ladderId,
+		func(tx pgx.Tx, ladder *tournament.Ladder, teamIds map[tournament.TeamKey]int64) error {
+			challenge, err := ladder.IssueChallenge(challengerRank, challengedRank, time.Now())
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidChallenge, err)
+			}
+
+			err = tx.QueryRow(
+				ctx,
+				sql,
+				ladderId,
+				teamIds[challenge.Challenger.Key()],
+				teamIds[challenge.Challenged.Key()],
+				int(challenge.Status),
+				challenge.IssuedAt,
+			).Scan(&challenge.Id)
+			if err != nil {
+				return fmt.Errorf("error while inserting challenge: %w", err)
+			}
+
+			res = challenge
+			return nil
+		},
+	)
+	return res, err
+}
+
+func AcceptChallenge(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengeId int64,
+) error {
+	return updateChallengeStatus(ctx, conn, userId, ladderId, challengeId,
+		(*tournament.Ladder).AcceptChallenge, tournament.ChallengeAccepted)
+}
+
+// DeclineChallenge stores that the challenged team refused an issued
+// challenge.
+func DeclineChallenge(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengeId int64,
+) error {
+	return updateChallengeStatus(ctx, conn, userId, ladderId, challengeId,
+		(*tournament.Ladder).DeclineChallenge, tournament.ChallengeDeclined)
+}
+
+// WithdrawChallenge stores that the challenger called off an open challenge.
+func WithdrawChallenge(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengeId int64,
+) error {
+	return updateChallengeStatus(ctx, conn, userId, ladderId, challengeId,
+		(*tournament.Ladder).WithdrawChallenge, tournament.ChallengeWithdrawn)
+}
+
+// Applies change to the challenge of the ladder, and stores its new status.
+func updateChallengeStatus(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengeId int64,
+	change func(*tournament.Ladder, int64) error,
+	status tournament.ChallengeStatus,
+) error {
+
+	const sql = `UPDATE challenge SET status = $1 WHERE id = $2;`
+
+	return withLadder(ctx, conn, userId, ladderId,
+		func(tx pgx.Tx, ladder *tournament.Ladder, _ map[tournament.TeamKey]int64) error {
+			if err := change(ladder, challengeId); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidChallenge, err)
+			}
+			if _, err := tx.Exec(ctx, sql, int(status), challengeId); err != nil {
+				return fmt.Errorf("error while updating challenge: %w", err)
+			}
+			return nil
+		},
+	)
+}
+
+// ReportChallengeResult stores the score of an accepted challenge and the
+// ranks that follow from it.
+func ReportChallengeResult(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	ladderId int64,
+	challengeId int64,
+	scoreChallenger, scoreChallenged int,
+) error {
+
+	const sqlChallenge = `
+		UPDATE challenge
+		SET status = $1, challenger_score = $2, challenged_score = $3
+		WHERE id = $4;`
+	const sqlRank = `UPDATE ladder_team SET rank = $1 WHERE ladder_id = $2 AND team_id = $3;`
+
+	return withLadder(ctx, conn, userId, ladderId,
+		func(tx pgx.Tx, ladder *tournament.Ladder, teamIds map[tournament.TeamKey]int64) error {
+			err := ladder.ReportResult(challengeId, scoreChallenger, scoreChallenged)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidChallenge, err)
+			}
+
+			_, err = tx.Exec(
+				ctx,
+				sqlChallenge,
+				int(tournament.ChallengeCompleted),
+				scoreChallenger,
+				scoreChallenged,
+				challengeId,
+			)
+			if err != nil {
+				return fmt.Errorf("error while recording challenge result: %w", err)
+			}
+
+			for i, team := range ladder.Teams {
+				if _, err := tx.Exec(ctx, sqlRank, i+1, ladderId, teamIds[team.Key()]); err != nil {
+					return fmt.Errorf("error while updating ranks: %w", err)
+				}
+			}
+			return nil
+		},
+	)
+}
@@ -2,7 +2,10 @@ package database
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/strang3nt/padel-services/internal/tournament"
@@ -16,45 +19,87 @@ type tournamentNameType struct {
 	TournamentId   int64
 	TournamentType string
 	TournamentName string
+	TournamentDate time.Time
+	Qualifiers     int
+	Consolation    bool
+	ThirdPlace     bool
+	RodeoOptions   []byte
 }
 
 const tournamentsByDate = `
-SELECT tournament.id, tournament_type.name, tournament.event_name
+SELECT tournament.id, tournament_type.name, tournament.event_name, tournament.tournament_date, tournament.qualifiers,
+	tournament.consolation, tournament.third_place, tournament.rodeo_options
 FROM tournament
 JOIN tournament_type ON tournament.tournament_type_id=tournament_type.id
 JOIN users ON tournament.user_id=users.id
-WHERE tournament.tournament_date::date = $1::date AND users.id = $2
+WHERE users.id = $2 AND (
+	tournament.tournament_date::date = $1::date OR EXISTS (
+		SELECT 1
+		FROM round_tournament
+		WHERE round_tournament.tournament_id = tournament.id
+			AND round_tournament.round_date::date = $1::date
+	)
+)
+`
+
+const tournamentById = `
+SELECT tournament.id, tournament_type.name, tournament.event_name, tournament.tournament_date, tournament.qualifiers,
+	tournament.consolation, tournament.third_place, tournament.rodeo_options
+FROM tournament
+JOIN tournament_type ON tournament.tournament_type_id=tournament_type.id
+JOIN users ON tournament.user_id=users.id
+WHERE tournament.id = $1 AND users.id = $2
 `
 
 type match struct {
+	MatchId     int64
 	RoundNumber int
 	Team1Id     int64
 	Team2Id     int64
 	CourtNumber int
+	Team1Score  int
+	Team2Score  int
+	MatchStatus int
+	Stage       int
+	RoundDate   *time.Time
 }
 
 const matchesByTournamentId = `
-SELECT round_number, team1_id, team2_id, court_number
+SELECT match.id, round_number, team1_id, team2_id, court_number, team1_score, team2_score, match_status, stage, round_date
 FROM "match"
 JOIN round_tournament ON match.id=round_tournament.match_id
 WHERE round_tournament.tournament_id=$1
-ORDER BY round_number
+ORDER BY round_number, court_number
 `
 
+// Registered tells whether the team is part of the tournament's roster, as
+// opposed to a team that was only formed for some matches. Registered teams
+// come first, sorted by seed. GroupNumber is 0 when the team is not part of
+// a group, Club is empty when the team does not play for a club.
 type team struct {
-	TeamId  int64
-	Person1 string
-	Person2 string
-	Gender  string
+	TeamId      int64
+	Person1     string
+	Person2     string
+	Gender      string
+	Registered  bool
+	GroupNumber int
+	Club        string
 }
 
 const teamsByTournamentId = `
-SELECT team.id, p1.name, p2.name, gender.name
+SELECT team.id, p1.name, COALESCE(p2.name, ''), gender.name, tournament_team.team_id IS NOT NULL,
+	COALESCE(tournament_team.group_number, 0), COALESCE(club.name, '')
 FROM team
 JOIN person p1 ON team.person1_id=p1.id
-JOIN person p2 ON team.person2_id=p2.id
+LEFT JOIN person p2 ON team.person2_id=p2.id
 JOIN gender ON team.gender_id=gender.id
+LEFT JOIN tournament_team ON tournament_team.team_id=team.id AND tournament_team.tournament_id=$1
+LEFT JOIN club ON tournament_team.club_id=club.id
 WHERE team.id IN (
+	SELECT team_id
+	FROM tournament_team
+	WHERE tournament_id=$1
+	UNION
 	SELECT team1_id
 	FROM "match"
 	JOIN round_tournament ON match.id=round_tournament.match_id
@@ -65,6 +110,7 @@ WHERE team.id IN (
 	JOIN round_tournament ON match.id=round_tournament.match_id
 	WHERE round_tournament.tournament_id=$1
 )
+ORDER BY tournament_team.seed, team.id
 `
 
 func GetTournamentsByDate(
@@ -84,39 +130,78 @@ func GetTournamentsByDate(
 	}
 
 	for _, id := range tournamentIds {
-		rows, err := conn.Query(ctx, matchesByTournamentId, id.TournamentId)
-		if err != nil {
-			return tournaments, fmt.Errorf("query error: %w", err)
-		}
-		matches, err := pgx.CollectRows(rows, pgx.RowToStructByPos[match])
+		t, err := getTournamentData(ctx, conn, id)
 		if err != nil {
-			return tournaments, fmt.Errorf("collectRows error: %v", err)
+			return tournaments, err
 		}
+		tournaments = append(tournaments, t)
+	}
 
-		rows, err = conn.Query(ctx, teamsByTournamentId, id.TournamentId)
-		if err != nil {
-			return tournaments, fmt.Errorf("query error: %w", err)
-		}
-		teams, err := pgx.CollectRows(rows, pgx.RowToStructByPos[team])
-		if err != nil {
-			return tournaments, fmt.Errorf("collectRows error: %v", err)
-		}
+	return tournaments, nil
 
-		tournaments = append(
-			tournaments,
-			buildTournamentData(
-				id.TournamentName,
-				tournamentDate,
-				matches,
-				teams,
-				id.TournamentType,
-			),
-		)
+}
 
+func GetTournamentById(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	tournamentId int64) (tournament.TournamentData, error) {
+
+	rows, err := conn.Query(ctx, tournamentById, tournamentId, userId)
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("query error: %w", err)
+	}
+	id, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByPos[tournamentNameType])
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("scan error: %w", err)
 	}
 
-	return tournaments, nil
+	return getTournamentData(ctx, conn, id)
+}
+
+func getTournamentData(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	id tournamentNameType) (tournament.TournamentData, error) {
+
+	rows, err := conn.Query(ctx, matchesByTournamentId, id.TournamentId)
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("query error: %w", err)
+	}
+	matches, err := pgx.CollectRows(rows, pgx.RowToStructByPos[match])
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	rows, err = conn.Query(ctx, teamsByTournamentId, id.TournamentId)
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("query error: %w", err)
+	}
+	teams, err := pgx.CollectRows(rows, pgx.RowToStructByPos[team])
+	if err != nil {
+		return tournament.TournamentData{}, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	data := buildTournamentData(
+		id.TournamentName,
+		id.TournamentDate,
+		matches,
+		teams,
+		id.TournamentType,
+	)
+	data.Id = id.TournamentId
+	data.Qualifiers = id.Qualifiers
+	data.Elimination = tournament.EliminationOptions{
+		Consolation: id.Consolation,
+		ThirdPlace:  id.ThirdPlace,
+	}
+	if id.RodeoOptions != nil {
+		if err := json.Unmarshal(id.RodeoOptions, &data.RodeoOptions); err != nil {
+			return tournament.TournamentData{}, fmt.Errorf("error while decoding rodeo options: %w", err)
+		}
+	}
 
+	return data, nil
 }
 
 func buildTournamentData(
@@ -127,75 +212,155 @@ func buildTournamentData(
 	tournamentType string) tournament.TournamentData {
 
 	teamsMap := make(map[int64]*tournament.Team)
-	matchesMap := make(map[int][]struct {
-		team1        int64
-		team2        int64
-		court_number int
-	})
+	matchesMap := make(map[int][]match)
 
 	for _, m := range matches {
-		curr, ok := matchesMap[m.RoundNumber]
-		if !ok {
-			matchesMap[m.RoundNumber] = []struct {
-				team1        int64
-				team2        int64
-				court_number int
-			}{
-				{m.Team1Id, m.Team2Id, m.CourtNumber},
-			}
-		} else {
-			curr = append(curr, struct {
-				team1        int64
-				team2        int64
-				court_number int
-			}{m.Team1Id, m.Team2Id, m.CourtNumber})
-			matchesMap[m.RoundNumber] = curr
-		}
+		matchesMap[m.RoundNumber] = append(matchesMap[m.RoundNumber], m)
+	}
+
+	hasRoster := false
+	for _, t := range teams {
+		hasRoster = hasRoster || t.Registered
 	}
 
 	teamsResult := make([]tournament.Team, 0)
+	var groups [][]tournament.Team
+	var clubs []tournament.Club
+	clubIndex := make(map[string]int)
 
 	for _, t := range teams {
 		person1 := tournament.Person{Id: t.Person1}
 		person2 := tournament.Person{Id: t.Person2}
 
-		teamsResult = append(
-			teamsResult,
-			tournament.Team{
-				Person1:    person1,
-				Person2:    person2,
-				TeamGender: tournament.GenderFromString(t.Gender),
-			},
-		)
+		team := tournament.Team{
+			Person1:    person1,
+			Person2:    person2,
+			TeamGender: tournament.GenderFromString(t.Gender),
+		}
+
+		// Tournaments stored before rosters existed only know the teams
+		// that played a match.
+		if t.Registered || !hasRoster {
+			teamsResult = append(teamsResult, team)
+		}
 
-		teamsMap[t.TeamId] = &teamsResult[len(teamsResult)-1]
+		if t.GroupNumber > 0 {
+			for len(groups) < t.GroupNumber {
+				groups = append(groups, nil)
+			}
+			groups[t.GroupNumber-1] = append(groups[t.GroupNumber-1], team)
+		}
+
+		// Squads keep the order of seeds, clubs the order of their first team.
+		if t.Club != "" {
+			i, ok := clubIndex[t.Club]
+			if !ok {
+				i = len(clubs)
+				clubIndex[t.Club] = i
+				clubs = append(clubs, tournament.Club{Name: t.Club})
+			}
+			clubs[i].Squad = append(clubs[i].Squad, team)
+		}
+
+		teamsMap[t.TeamId] = &team
 
 	}
 
-	rounds := make([]tournament.Round, len(matchesMap))
+	// Rounds are sorted by their stored number, that may have gaps.
+	rounds := make([]tournament.Round, 0, len(matchesMap))
 
-	for k, v := range matchesMap {
+	for _, k := range slices.Sorted(maps.Keys(matchesMap)) {
+		v := matchesMap[k]
 		round := make([]tournament.Match, 0)
 
-		for _, t := range v {
-			team1 := teamsMap[t.team1]
-			team2 := teamsMap[t.team2]
+		for _, m := range v {
+			team1 := teamsMap[m.Team1Id]
+			team2 := teamsMap[m.Team2Id]
 			round = append(round, tournament.Match{
+				Id:          m.MatchId,
 				TeamA:       team1,
 				TeamB:       team2,
-				MatchStatus: tournament.MatchScheduled,
-				CourtId:     t.court_number})
+				MatchStatus: tournament.MatchStatus(m.MatchStatus),
+				CourtId:     m.CourtNumber,
+				ScoreA:      m.Team1Score,
+				ScoreB:      m.Team2Score,
+				Stage:       tournament.MatchStage(m.Stage),
+			})
 		}
-		rounds[k] = tournament.Round{Matches: round}
+		r := tournament.Round{Matches: round}
+		if v[0].RoundDate != nil {
+			r.Date = *v[0].RoundDate
+		}
+		rounds = append(rounds, r)
 	}
 
 	tournamentTypeObj, _ := tournament.TournamentTypeFromString(tournamentType)
 
-	return tournament.MakeTournamentData(
+	data := tournament.MakeTournamentData(
 		tournamentName,
 		startDate,
 		teamsResult,
 		rounds,
 		tournamentTypeObj,
 	)
+	data.Groups = groups
+	data.Clubs = clubs
+
+	return data
+}
+
+type pastMatch struct {
+	TeamAPerson1 string
+	TeamAPerson2 string
+	TeamBPerson1 string
+	TeamBPerson2 string
+}
+
+const pastMatchesByUserId = `
+SELECT a1.name, COALESCE(a2.name, ''), b1.name, COALESCE(b2.name, '')
+FROM "match"
+JOIN round_tournament ON match.id=round_tournament.match_id
+JOIN tournament ON round_tournament.tournament_id=tournament.id
+JOIN team team_a ON match.team1_id=team_a.id
+JOIN team team_b ON match.team2_id=team_b.id
+JOIN person a1 ON team_a.person1_id=a1.id
+LEFT JOIN person a2 ON team_a.person2_id=a2.id
+JOIN person b1 ON team_b.person1_id=b1.id
+LEFT JOIN person b2 ON team_b.person2_id=b2.id
+WHERE tournament.user_id=$1 AND tournament.id <> $2
+`
+
+// GetPairingHistory counts the partners and the opponents of every match of
+// the user's tournaments, but the tournament exceptId, e.g. the one being
+// rescheduled.
+func GetPairingHistory(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	exceptId int64) (*tournament.PairingHistory, error) {
+
+	rows, err := conn.Query(ctx, pastMatchesByUserId, userId, exceptId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	matches, err := pgx.CollectRows(rows, pgx.RowToStructByPos[pastMatch])
+	if err != nil {
+		return nil, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	history := tournament.NewPairingHistory()
+	for _, m := range matches {
+		history.AddMatch(
+			tournament.Team{
+				Person1: tournament.Person{Id: m.TeamAPerson1},
+				Person2: tournament.Person{Id: m.TeamAPerson2},
+			},
+			tournament.Team{
+				Person1: tournament.Person{Id: m.TeamBPerson1},
+				Person2: tournament.Person{Id: m.TeamBPerson2},
+			},
+		)
+	}
+
+	return history, nil
 }
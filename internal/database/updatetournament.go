@@ -0,0 +1,302 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTournamentNotFound = errors.New("tournament not found")
+	// ErrRoundsChanged is returned when rounds were added to a tournament
+	// after the new rounds were generated from it.
+	ErrRoundsChanged = errors.New("the rounds of the tournament changed")
+	// ErrDrawNotAllowed is returned for a draw in a match of an elimination
+	// bracket, that needs a winner.
+	ErrDrawNotAllowed = errors.New("elimination matches cannot end in a draw")
+)
+
+// Checks that the tournament belongs to the user, and locks it until the
+// transaction ends, so that updates of the same tournament run one at a time.
+func queryCheckTournamentOwner(
+	ctx context.Context,
+	tx pgx.Tx,
+	userId int64,
+	tournamentId int64,
+) error {
+
+	const sql = `SELECT id FROM tournament WHERE id = $1 AND user_id = $2 FOR UPDATE;`
+
+	var id int64
+	if err := tx.QueryRow(ctx, sql, tournamentId, userId).Scan(&id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTournamentNotFound
+		}
+		return fmt.Errorf("error while retrieving tournament: %w", err)
+	}
+
+	return nil
+}
+
+func queryTeamIds(
+	ctx context.Context,
+	tx pgx.Tx,
+	tournamentId int64,
+) (map[tournament.TeamKey]int64, error) {
+
+	rows, err := tx.Query(ctx, teamsByTournamentId, tournamentId)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	teams, err := pgx.CollectRows(rows, pgx.RowToStructByPos[team])
+	if err != nil {
+		return nil, fmt.Errorf("collectRows error: %v", err)
+	}
+
+	teamIds := make(map[tournament.TeamKey]int64)
+	for _, t := range teams {
+		teamIds[tournament.TeamKey{
+			Person1:    t.Person1,
+			Person2:    t.Person2,
+			TeamGender: tournament.GenderFromString(t.Gender),
+		}] = t.TeamId
+	}
+
+	return teamIds, nil
+}
+
+// AddRound stores round after the last round of an existing tournament.
+// roundsNumber is the number of rounds the round was generated from.
+func AddRound(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	tournamentId int64,
+	roundsNumber int,
+	round tournament.Round,
+) error {
+	return AddRounds(ctx, conn, userId, tournamentId, roundsNumber, []tournament.Round{round})
+}
+
+// AddRounds stores rounds, in a single transaction, after the last round of
+// an existing tournament. roundsNumber is the number of rounds the new
+// rounds were generated from: when rounds were added in the meantime,
+// ErrRoundsChanged is returned and nothing is stored.
+func AddRounds(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	tournamentId int64,
+	roundsNumber int,
+	rounds []tournament.Round,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("msg rolling back transaction: %v", err)
+		}
+	}()
+
+	if err := queryCheckTournamentOwner(ctx, tx, userId, tournamentId); err != nil {
+		return err
+	}
+
+	// Round numbers are stored starting from 0.
+	const sqlNextRound = `
+		SELECT COALESCE(MAX(round_number) + 1, 0)
+		FROM round_tournament
+		WHERE tournament_id = $1;`
+
+	var nextRound int
+	if err := tx.QueryRow(ctx, sqlNextRound, tournamentId).Scan(&nextRound); err != nil {
+		return fmt.Errorf("error while retrieving last round: %w", err)
+	}
+	if nextRound != roundsNumber {
+		return ErrRoundsChanged
+	}
+
+	teamIds, err := queryTeamIds(ctx, tx, tournamentId)
+	if err != nil {
+		return err
+	}
+
+	for i, round := range rounds {
+		err := queryCreateRound(ctx, tx, tournamentId, nextRound+i, round, teamIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordMatchResult stores the score of a match and marks it as completed.
+// Matches of elimination brackets need a winner, ErrDrawNotAllowed is
+// returned for a draw.
+func RecordMatchResult(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	tournamentId int64,
+	matchId int64,
+	scoreA, scoreB int,
+) error {
+
+	const sqlStage = `
+		SELECT match.stage, tournament_type.name
+		FROM "match"
+		JOIN round_tournament ON round_tournament.match_id = match.id
+		JOIN tournament ON tournament.id = round_tournament.tournament_id
+		JOIN tournament_type ON tournament_type.id = tournament.tournament_type_id
+		WHERE match.id = $1 AND tournament.id = $2 AND tournament.user_id = $3;`
+
+	var stage int
+	var typeName string
+	err := conn.QueryRow(ctx, sqlStage, matchId, tournamentId, userId).Scan(&stage, &typeName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("match %d: %w", matchId, ErrTournamentNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("error while retrieving match: %w", err)
+	}
+	tournamentType, _ := tournament.TournamentTypeFromString(typeName)
+	if scoreA == scoreB && tournament.IsEliminationStage(tournamentType, tournament.MatchStage(stage)) {
+		return ErrDrawNotAllowed
+	}
+
+	const sql = `
+		UPDATE "match"
+		SET team1_score = $1, team2_score = $2, match_status = $3
+		WHERE match.id = $4 AND match.id IN (
+			SELECT round_tournament.match_id
+			FROM round_tournament
+			JOIN tournament ON tournament.id = round_tournament.tournament_id
+			WHERE tournament.id = $5 AND tournament.user_id = $6
+		);`
+
+	tag, err := conn.Exec(
+		ctx,
+		sql,
+		scoreA,
+		scoreB,
+		int(tournament.MatchCompleted),
+		matchId,
+		tournamentId,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("error while recording match result: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("match %d: %w", matchId, ErrTournamentNotFound)
+	}
+
+	return nil
+}
+
+// RescheduleTournament replaces the rounds of an existing tournament from
+// the round number fromRound on, numbered from 1, with the rounds of t from
+// that round on. The roster becomes the teams of t, seeded in their order:
+// teams that are not part of it anymore keep their matches in the earlier
+// rounds. The options of rodeos are replaced with the ones of t.
+func RescheduleTournament(
+	ctx context.Context,
+	conn *pgxpool.Pool,
+	userId int64,
+	tournamentId int64,
+	fromRound int,
+	t tournament.Tournament,
+) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("msg rolling back transaction: %v", err)
+		}
+	}()
+
+	if err := queryCheckTournamentOwner(ctx, tx, userId, tournamentId); err != nil {
+		return err
+	}
+
+	// Round numbers are stored starting from 0.
+	const deleteRounds = `
+		WITH removed AS (
+			DELETE FROM round_tournament
+			WHERE tournament_id = $1 AND round_number >= $2
+			RETURNING match_id
+		)
+		DELETE FROM "match" WHERE id IN (SELECT match_id FROM removed);`
+
+	if _, err := tx.Exec(ctx, deleteRounds, tournamentId, fromRound-1); err != nil {
+		return fmt.Errorf("error while deleting rounds: %w", err)
+	}
+
+	rodeoOptions, err := marshalRodeoOptions(t)
+	if err != nil {
+		return err
+	}
+	const updateOptions = `UPDATE tournament SET rodeo_options = $2 WHERE id = $1;`
+	if _, err := tx.Exec(ctx, updateOptions, tournamentId, rodeoOptions); err != nil {
+		return fmt.Errorf("error while updating rodeo options: %w", err)
+	}
+
+	teamIds, err := queryTeamIds(ctx, tx, tournamentId)
+	if err != nil {
+		return err
+	}
+
+	roster := make([]int64, 0, len(t.GetTeams()))
+	for seed, team := range t.GetTeams() {
+		teamId, ok := teamIds[team.Key()]
+		if !ok {
+			teamId, err = queryInsertTeam(ctx, tx, team)
+			if err != nil {
+				return err
+			}
+			teamIds[team.Key()] = teamId
+		}
+		roster = append(roster, teamId)
+
+		if err := queryRegisterTeam(ctx, tx, tournamentId, teamId, seed, 0, nil); err != nil {
+			return err
+		}
+	}
+
+	const unregisterTeams = `
+		DELETE FROM tournament_team
+		WHERE tournament_id = $1 AND NOT (team_id = ANY($2));`
+
+	if _, err := tx.Exec(ctx, unregisterTeams, tournamentId, roster); err != nil {
+		return fmt.Errorf("error while unregistering teams: %w", err)
+	}
+
+	rounds := t.GetRounds()
+	for roundIndex := fromRound - 1; roundIndex < len(rounds); roundIndex++ {
+		err := queryCreateRound(ctx, tx, tournamentId, roundIndex, rounds[roundIndex], teamIds)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
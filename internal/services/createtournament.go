@@ -2,6 +2,9 @@ package services
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"fmt"
 	"log"
 	"runtime"
 	"strings"
@@ -10,12 +13,49 @@ import (
 	"github.com/strang3nt/padel-services/internal/tournament"
 )
 
+// FormatOptions holds the parameters that only some tournament types use.
+type FormatOptions struct {
+	GroupsNumber      int
+	Qualifiers        int
+	Legs              int
+	DaysBetweenRounds int
+	Consolation       bool
+	ThirdPlace        bool
+	// Rubbers and Clubs are used by interclub leagues, whose teams are the
+	// pairs of the club squads.
+	Rubbers int
+	Clubs   []tournament.Club
+	// MatchDuration and Changeover give the rounds of a rodeo their start
+	// time.
+	MatchDuration time.Duration
+	Changeover    time.Duration
+	// Courts, when given, replace availableCourts for rodeos.
+	Courts []tournament.Court
+	// Availability limits the rounds of a rodeo some people can play in.
+	Availability []tournament.Availability
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights tournament.CourtWeights
+	// BalanceRests spreads the rests of a rodeo evenly.
+	BalanceRests bool
+	// History holds the pairings of past tournaments, that rodeos avoid.
+	History *tournament.PairingHistory
+	// Pairing tells which people single player rodeos can pair, by gender.
+	Pairing tournament.PairingMode
+	// FixedPairs are couples that always play together in single player
+	// rodeos.
+	FixedPairs []tournament.Team
+	// Constraints are the people that must never be partners, and that must
+	// never or must face each other, in rodeos.
+	Constraints tournament.Constraints
+}
+
 func CreateTournament(
 	tournamentName string,
 	tournamentType string,
 	dateStart time.Time,
 	teams []tournament.Team,
-	totalRounds, availableCourts int) tournament.Tournament {
+	totalRounds, availableCourts int,
+	options FormatOptions) (tournament.Tournament, error) {
 
 	switch tournamentType {
 	case "Rodeo":
@@ -23,6 +63,14 @@ func CreateTournament(
 		rodeo_factory := tournament.RodeoFactory{
 			MaxRounds:       totalRounds,
 			AvailableCourts: availableCourts,
+			MatchDuration:   options.MatchDuration,
+			Changeover:      options.Changeover,
+			Courts:          options.Courts,
+			Availability:    options.Availability,
+			CourtWeights:    options.CourtWeights,
+			BalanceRests:    options.BalanceRests,
+			History:         options.History,
+			Constraints:     options.Constraints,
 		}
 
 		rodeoInstance, err := rodeo_factory.GetFirstValidTournament(
@@ -34,18 +82,18 @@ func CreateTournament(
 		)
 		if err != nil {
 			log.Printf("error while creating tournament: %v", err)
-			return nil
+			return nil, rodeo_factory.Diagnose(teams, err)
 		}
 
-		return rodeoInstance
+		return rodeoInstance, nil
 	case "SinglePlayerRodeo":
 		log.Print("creating single player rodeo")
 
-		peopleMap := make(map[tournament.Person]any)
+		peopleMap := make(map[string]tournament.Person)
 
 		for _, p := range tournament.GetPeople(teams) {
 			if p.Id != "" {
-				peopleMap[p] = struct{}{}
+				peopleMap[p.Id] = p
 			}
 		}
 
@@ -53,6 +101,14 @@ func CreateTournament(
 			MaxRounds:       totalRounds,
 			AvailableCourts: availableCourts,
 			People:          peopleMap,
+			MatchDuration:   options.MatchDuration,
+			Changeover:      options.Changeover,
+			Availability:    options.Availability,
+			CourtWeights:    options.CourtWeights,
+			History:         options.History,
+			Pairing:         options.Pairing,
+			FixedPairs:      options.FixedPairs,
+			Constraints:     options.Constraints,
 		}
 
 		rodeoInstance, err := rodeo_factory.GetFirstValidTournament(
@@ -63,13 +119,186 @@ func CreateTournament(
 		)
 		if err != nil {
 			log.Printf("error while creating tournament: %v", err)
-			return nil
+			return nil, rodeo_factory.Diagnose(err)
+		}
+
+		return rodeoInstance, nil
+	case "Americano":
+		log.Print("creating americano")
+
+		peopleMap := make(map[string]tournament.Person)
+
+		for _, p := range tournament.GetPeople(teams) {
+			if p.Id != "" {
+				peopleMap[p.Id] = p
+			}
+		}
+
+		americanoFactory := tournament.AmericanoFactory{
+			MaxRounds:       totalRounds,
+			AvailableCourts: availableCourts,
+			People:          peopleMap,
+			CourtWeights:    options.CourtWeights,
+		}
+
+		americanoInstance, err := americanoFactory.MakeTournament(tournamentName, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return americanoInstance, nil
+	case "Mexicano":
+		log.Print("creating mexicano")
+
+		peopleMap := make(map[string]tournament.Person)
+
+		for _, p := range tournament.GetPeople(teams) {
+			if p.Id != "" {
+				peopleMap[p.Id] = p
+			}
+		}
+
+		mexicanoFactory := tournament.MexicanoFactory{
+			AvailableCourts: availableCourts,
+			People:          peopleMap,
+		}
+
+		mexicanoInstance, err := mexicanoFactory.MakeTournament(tournamentName, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
 		}
 
-		return rodeoInstance
+		return mexicanoInstance, nil
+	case "Knockout":
+		log.Print("creating knockout")
+
+		knockoutFactory := tournament.KnockoutFactory{
+			AvailableCourts: availableCourts,
+			Elimination: tournament.EliminationOptions{
+				Consolation: options.Consolation,
+				ThirdPlace:  options.ThirdPlace,
+			},
+			CourtWeights: options.CourtWeights,
+		}
+
+		knockoutInstance, err := knockoutFactory.MakeTournament(tournamentName, teams, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return knockoutInstance, nil
+	case "GroupKnockout":
+		log.Print("creating group stage and knockout")
+
+		groupKnockoutFactory := tournament.GroupKnockoutFactory{
+			AvailableCourts: availableCourts,
+			GroupsNumber:    options.GroupsNumber,
+			Qualifiers:      options.Qualifiers,
+			Elimination: tournament.EliminationOptions{
+				Consolation: options.Consolation,
+				ThirdPlace:  options.ThirdPlace,
+			},
+			CourtWeights: options.CourtWeights,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		groupKnockoutInstance, err := groupKnockoutFactory.MakeTournament(
+			ctx,
+			tournamentName,
+			teams,
+			dateStart,
+		)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return groupKnockoutInstance, nil
+	case "RoundRobin":
+		log.Print("creating round robin")
+
+		roundRobinFactory := tournament.RoundRobinFactory{
+			AvailableCourts:   availableCourts,
+			Legs:              options.Legs,
+			DaysBetweenRounds: options.DaysBetweenRounds,
+			CourtWeights:      options.CourtWeights,
+		}
+
+		roundRobinInstance, err := roundRobinFactory.MakeTournament(tournamentName, teams, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return roundRobinInstance, nil
+	case "Swiss":
+		log.Print("creating swiss")
+
+		swissFactory := tournament.SwissFactory{
+			MaxRounds:       totalRounds,
+			AvailableCourts: availableCourts,
+			CourtWeights:    options.CourtWeights,
+		}
+
+		swissInstance, err := swissFactory.MakeTournament(tournamentName, teams, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return swissInstance, nil
+	case "KingOfTheCourt":
+		log.Print("creating king of the court")
+
+		kingFactory := tournament.KingOfTheCourtFactory{
+			AvailableCourts: availableCourts,
+		}
+
+		kingInstance, err := kingFactory.MakeTournament(tournamentName, teams, dateStart)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return kingInstance, nil
+	case "Interclub":
+		log.Print("creating interclub")
+
+		interclubFactory := tournament.InterclubFactory{
+			Rubbers:           options.Rubbers,
+			Legs:              options.Legs,
+			DaysBetweenRounds: options.DaysBetweenRounds,
+		}
+
+		interclubInstance, err := interclubFactory.MakeTournament(
+			tournamentName,
+			options.Clubs,
+			dateStart,
+		)
+		if err != nil {
+			log.Printf("error while creating tournament: %v", err)
+			return nil, err
+		}
+
+		return interclubInstance, nil
 	default:
-		return nil
+		return nil, fmt.Errorf("unknown tournament type %q", tournamentType)
+	}
+}
+
+// Diagnosis tells why a tournament could not be created, from the error
+// CreateTournament returned.
+func Diagnosis(err error) *tournament.Infeasibility {
+	var infeasibility *tournament.Infeasibility
+	if errors.As(err, &infeasibility) {
+		return infeasibility
 	}
+	return &tournament.Infeasibility{Reason: tournament.Unsatisfiable, Message: err.Error()}
 }
 
 func MakeTeamsFromMessage(stringteams *bufio.Scanner) ([]tournament.Team, error) {
@@ -84,8 +313,9 @@ func MakeTeamsFromMessage(stringteams *bufio.Scanner) ([]tournament.Team, error)
 			continue
 		}
 
-		person1 := tournament.Person{Id: strings.TrimSpace(row[0])}
-		person2 := tournament.Person{Id: strings.TrimSpace(row[1])}
+		// The file only gives the gender of the team.
+		person1 := tournament.Person{Id: strings.TrimSpace(row[0]), Gender: tournament.Unknown}
+		person2 := tournament.Person{Id: strings.TrimSpace(row[1]), Gender: tournament.Unknown}
 
 		g := tournament.Else
 
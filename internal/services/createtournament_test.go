@@ -58,7 +58,13 @@ Chiarelli Tommaso, Ercolani Francesco`
 
 	scanner := bufio.NewScanner(strings.NewReader(msg))
 	teams, err := MakeTeamsFromMessage(scanner)
-	rodeo := CreateTournament("Super rodeo", "Rodeo", time.Now(), teams, 8, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rodeo, err := CreateTournament("Super rodeo", "Rodeo", time.Now(), teams, 8, 5, FormatOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	t.Logf("tournament created successfully: %+v", rodeo)
 
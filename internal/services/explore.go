@@ -0,0 +1,25 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+)
+
+// ExploreSchedules lists the combinations of rounds and courts a tournament
+// of the given number of participants can be generated with: teams for
+// rodeos, people for single player rodeos.
+func ExploreSchedules(
+	tournamentType string,
+	participants, maxRounds, maxCourts int,
+) ([]tournament.ScheduleOption, error) {
+
+	switch tournamentType {
+	case "Rodeo":
+		return tournament.ExploreRodeo(participants, maxRounds, maxCourts), nil
+	case "SinglePlayerRodeo":
+		return tournament.ExploreSinglePlayerRodeo(participants, maxRounds, maxCourts), nil
+	default:
+		return nil, fmt.Errorf("cannot explore schedules of tournament type %q", tournamentType)
+	}
+}
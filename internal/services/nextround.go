@@ -0,0 +1,65 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+)
+
+// MakeNextRound generates the next round of a tournament whose rounds depend
+// on the results recorded so far. When availableCourts is not positive, the
+// courts used by the last round are assumed to be available.
+func MakeNextRound(
+	t tournament.Tournament,
+	availableCourts int,
+	courtWeights tournament.CourtWeights,
+) (tournament.Round, error) {
+
+	if t == nil {
+		return tournament.Round{}, errors.New("unknown tournament type")
+	}
+
+	if availableCourts <= 0 {
+		rounds := t.GetRounds()
+		if len(rounds) > 0 {
+			availableCourts = len(rounds[len(rounds)-1].Matches)
+		}
+	}
+
+	switch t := t.(type) {
+	case *tournament.Mexicano:
+		mexicanoFactory := tournament.MexicanoFactory{
+			AvailableCourts: availableCourts,
+		}
+		return mexicanoFactory.MakeNextRound(t)
+	case *tournament.Knockout:
+		knockoutFactory := tournament.KnockoutFactory{
+			AvailableCourts: availableCourts,
+			CourtWeights:    courtWeights,
+		}
+		return knockoutFactory.MakeNextRound(t)
+	case *tournament.GroupKnockout:
+		groupKnockoutFactory := tournament.GroupKnockoutFactory{
+			AvailableCourts: availableCourts,
+			CourtWeights:    courtWeights,
+		}
+		return groupKnockoutFactory.MakeNextRound(t)
+	case *tournament.Swiss:
+		swissFactory := tournament.SwissFactory{
+			AvailableCourts: availableCourts,
+			CourtWeights:    courtWeights,
+		}
+		return swissFactory.MakeNextRound(t)
+	case *tournament.KingOfTheCourt:
+		kingFactory := tournament.KingOfTheCourtFactory{
+			AvailableCourts: availableCourts,
+		}
+		return kingFactory.MakeNextRound(t)
+	default:
+		return tournament.Round{}, fmt.Errorf(
+			"tournament %s does not support generating rounds from results",
+			t.GetName(),
+		)
+	}
+}
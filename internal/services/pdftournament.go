@@ -11,12 +11,14 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/strang3nt/padel-services/internal/tournament"
 )
 
 type Match struct {
 	Court       string
+	Group       string
 	TeamA       string
 	ScoreA      string
 	TeamB       string
@@ -26,14 +28,29 @@ type Match struct {
 
 type Round struct {
 	RoundNumber int
+	Date        string
 	Matches     []Match
 	Resting     []string
 }
 
+type BracketRound struct {
+	Name    string
+	Matches []Match
+}
+
+type Group struct {
+	Name  string
+	Teams []string
+}
+
 type TournamentData struct {
-	Name      string
-	StartDate string
-	Rounds    []Round
+	Name        string
+	StartDate   string
+	Rounds      []Round
+	Groups      []Group
+	Bracket     []BracketRound
+	ThirdPlace  []BracketRound
+	Consolation []BracketRound
 }
 
 type TemplateData struct {
@@ -45,6 +62,14 @@ type TournamentType int
 const (
 	Rodeo TournamentType = iota
 	SinglePlayerRodeo
+	Americano
+	Mexicano
+	Knockout
+	GroupKnockout
+	RoundRobin
+	Swiss
+	KingOfTheCourt
+	Interclub
 )
 
 type TournamentPdfGenerator struct {
@@ -55,6 +80,16 @@ type TournamentPdfGenerator struct {
 //go:embed templates/*
 var templates embed.FS
 var templateRodeoSchedule, _ = template.ParseFS(templates, "templates/template_rodeo_schedule.html")
+var templateKnockoutBracket, _ = template.ParseFS(
+	templates,
+	"templates/template_knockout_bracket.html",
+	"templates/template_bracket.html",
+)
+var templateGroupSchedule, _ = template.ParseFS(
+	templates,
+	"templates/template_group_schedule.html",
+	"templates/template_bracket.html",
+)
 
 func MakeTournamentPdfGenerator() TournamentPdfGenerator {
 	chromeExecutable := "chromium-browser"
@@ -67,11 +102,45 @@ func MakeTournamentPdfGenerator() TournamentPdfGenerator {
 		templatesDirs: map[TournamentType]*template.Template{
 			Rodeo:             templateRodeoSchedule,
 			SinglePlayerRodeo: templateRodeoSchedule,
+			Americano:         templateRodeoSchedule,
+			Mexicano:          templateRodeoSchedule,
+			Knockout:          templateKnockoutBracket,
+			GroupKnockout:     templateGroupSchedule,
+			RoundRobin:        templateRodeoSchedule,
+			Swiss:             templateRodeoSchedule,
+			KingOfTheCourt:    templateRodeoSchedule,
+			Interclub:         templateRodeoSchedule,
 		},
 	}
 
 }
 
+// GetTemplateType returns the template used to print a kind of tournament.
+func GetTemplateType(t tournament.TournamentType) TournamentType {
+	switch t {
+	case tournament.TournamentTypeSinglePlayerRodeo:
+		return SinglePlayerRodeo
+	case tournament.TournamentTypeAmericano:
+		return Americano
+	case tournament.TournamentTypeMexicano:
+		return Mexicano
+	case tournament.TournamentTypeKnockout:
+		return Knockout
+	case tournament.TournamentTypeGroupKnockout:
+		return GroupKnockout
+	case tournament.TournamentTypeRoundRobin:
+		return RoundRobin
+	case tournament.TournamentTypeSwiss:
+		return Swiss
+	case tournament.TournamentTypeKingOfTheCourt:
+		return KingOfTheCourt
+	case tournament.TournamentTypeInterclub:
+		return Interclub
+	default:
+		return Rodeo
+	}
+}
+
 func (t TournamentPdfGenerator) CreatePdfTournament(
 	data TemplateData,
 	tt TournamentType,
@@ -175,22 +244,31 @@ func (tt TournamentPdfGenerator) generatePDFWithHeadlessChrome(
 
 func FromTournamentToTemplateData(tournament tournament.Tournament) TemplateData {
 
+	var groups []Group
+	teamGroups := make(map[string]string)
+	if g, ok := tournament.(groupTournament); ok {
+		groups = makeGroupsTemplateData(g.GetGroups())
+		for _, group := range groups {
+			for _, team := range group.Teams {
+				teamGroups[team] = group.Name
+			}
+		}
+	}
+
+	dateLayout := roundDateLayout(tournament.GetDateStart(), tournament.GetRounds())
+
 	var rounds []Round
 	for roundIndex, round := range tournament.GetRounds() {
 
 		var matches []Match
 		for _, match := range round.Matches {
 
-			surnamePerson1TeamA := strings.Split(match.TeamA.Person1.Id, " ")
-			surnamePerson2TeamA := strings.Split(match.TeamA.Person2.Id, " ")
-			surnamePerson1TeamB := strings.Split(match.TeamB.Person1.Id, " ")
-			surnamePerson2TeamB := strings.Split(match.TeamB.Person2.Id, " ")
-
 			matches = append(matches, Match{
 				Court:       strconv.Itoa(match.CourtId),
-				TeamA:       surnamePerson1TeamA[len(surnamePerson1TeamA)-1] + " - " + surnamePerson2TeamA[len(surnamePerson2TeamA)-1],
+				Group:       groupOf(teamGroups, match),
+				TeamA:       teamSurnames(match.TeamA),
 				ScoreA:      "",
-				TeamB:       surnamePerson1TeamB[len(surnamePerson1TeamB)-1] + " - " + surnamePerson2TeamB[len(surnamePerson2TeamB)-1],
+				TeamB:       teamSurnames(match.TeamB),
 				ScoreB:      "",
 				RoundNumber: roundIndex + 1,
 			})
@@ -198,22 +276,172 @@ func FromTournamentToTemplateData(tournament tournament.Tournament) TemplateData
 
 		resting := tournament.GetResting(roundIndex, "-")
 
+		var date string
+		if !round.Date.IsZero() {
+			date = round.Date.Format(dateLayout)
+		}
+
 		rounds = append(rounds, Round{
 			RoundNumber: roundIndex + 1,
+			Date:        date,
 			Matches:     matches,
 			Resting:     resting,
 		})
 	}
 
+	var bracket []BracketRound
+	if b, ok := tournament.(bracketTournament); ok {
+		bracket = makeBracketTemplateData(b.GetBracket())
+	}
+
+	var thirdPlace, consolation []BracketRound
+	if e, ok := tournament.(eliminationTournament); ok {
+		thirdPlace = makeThirdPlaceTemplateData(e.GetThirdPlaceMatch())
+		consolation = makeBracketTemplateData(e.GetConsolationBracket())
+	}
+
 	return TemplateData{
 		Tournament: TournamentData{
-			Name:      tournament.GetName(),
-			StartDate: tournament.GetDateStart().Format("2006-01-02"),
-			Rounds:    rounds,
+			Name:        tournament.GetName(),
+			StartDate:   tournament.GetDateStart().Format("2006-01-02"),
+			Rounds:      rounds,
+			Groups:      groups,
+			Bracket:     bracket,
+			ThirdPlace:  thirdPlace,
+			Consolation: consolation,
 		},
 	}
 }
 
+type bracketTournament interface {
+	GetBracket() [][]tournament.BracketMatch
+}
+
+type eliminationTournament interface {
+	GetThirdPlaceMatch() *tournament.Match
+	GetConsolationBracket() [][]tournament.BracketMatch
+}
+
+type groupTournament interface {
+	GetGroups() [][]tournament.Team
+}
+
+func makeGroupsTemplateData(groups [][]tournament.Team) []Group {
+	res := make([]Group, len(groups))
+	for i, group := range groups {
+		res[i].Name = string(rune('A' + i))
+		for _, team := range group {
+			res[i].Teams = append(res[i].Teams, teamSurnames(&team))
+		}
+	}
+	return res
+}
+
+// Returns the group of a group stage match, empty for the other matches.
+func groupOf(teamGroups map[string]string, match tournament.Match) string {
+	if match.Stage != tournament.StageGroup {
+		return ""
+	}
+	return teamGroups[teamSurnames(match.TeamA)]
+}
+
+// Rounds played in the same day as the tournament start show their start
+// time, rounds played over different days show their date.
+func roundDateLayout(dateStart time.Time, rounds []tournament.Round) string {
+	for _, round := range rounds {
+		if round.Date.IsZero() {
+			continue
+		}
+		y1, m1, d1 := round.Date.Date()
+		y2, m2, d2 := dateStart.Date()
+		if y1 != y2 || m1 != m2 || d1 != d2 {
+			return "2006-01-02"
+		}
+	}
+	return "15:04"
+}
+
+func teamSurnames(team *tournament.Team) string {
+	if team == nil {
+		return ""
+	}
+
+	surnamePerson1 := strings.Split(team.Person1.Id, " ")
+	// Tie-breaks of a single player rodeo are played by single players.
+	if team.Person2.IsNil() {
+		return surnamePerson1[len(surnamePerson1)-1]
+	}
+	surnamePerson2 := strings.Split(team.Person2.Id, " ")
+
+	return surnamePerson1[len(surnamePerson1)-1] + " - " + surnamePerson2[len(surnamePerson2)-1]
+}
+
+func bracketRoundName(round int, totalRounds int) string {
+	switch totalRounds - round {
+	case 1:
+		return "FINALE"
+	case 2:
+		return "SEMIFINALI"
+	case 3:
+		return "QUARTI DI FINALE"
+	case 4:
+		return "OTTAVI DI FINALE"
+	default:
+		return fmt.Sprintf("TURNO %d", round+1)
+	}
+}
+
+func makeBracketTemplateData(bracket [][]tournament.BracketMatch) []BracketRound {
+	var res []BracketRound
+
+	for roundIndex, round := range bracket {
+		var matches []Match
+		for _, bm := range round {
+			m := Match{
+				TeamA:       teamSurnames(bm.TeamA),
+				TeamB:       teamSurnames(bm.TeamB),
+				RoundNumber: roundIndex + 1,
+			}
+			if bm.TeamA == nil {
+				m.TeamA = bm.SourceA
+			}
+			if bm.TeamB == nil {
+				m.TeamB = bm.SourceB
+			}
+			if bm.Bye {
+				m.TeamB = "BYE"
+			}
+			if bm.Match != nil {
+				m.Court = strconv.Itoa(bm.Match.CourtId)
+				if bm.Match.MatchStatus == tournament.MatchCompleted {
+					m.ScoreA = strconv.Itoa(bm.Match.ScoreA)
+					m.ScoreB = strconv.Itoa(bm.Match.ScoreB)
+				}
+			}
+			matches = append(matches, m)
+		}
+
+		res = append(res, BracketRound{
+			Name:    bracketRoundName(roundIndex, len(bracket)),
+			Matches: matches,
+		})
+	}
+
+	return res
+}
+
+func makeThirdPlaceTemplateData(m *tournament.Match) []BracketRound {
+	if m == nil {
+		return nil
+	}
+
+	res := makeBracketTemplateData([][]tournament.BracketMatch{
+		{{TeamA: m.TeamA, TeamB: m.TeamB, Match: m}},
+	})
+	res[0].Name = ""
+	return res
+}
+
 func FromTournamentDataToTemplateData(tournament tournament.TournamentData) TemplateData {
 
 	res := tournament.ToTournament()
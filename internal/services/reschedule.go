@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+)
+
+// Reschedule regenerates the rounds of a tournament from round fromRound on,
+// for the given teams. Rodeos are regenerated with the options they were made
+// with, history holds the past pairings for rodeos that avoid them. Mexicanos
+// are paired one round at a time, so only fromRound is paired again. When
+// given, availableCourts and courtWeights replace the stored ones; when no
+// number of courts is known, the courts used by the busiest round are assumed
+// to be available.
+func Reschedule(
+	t tournament.Tournament,
+	fromRound int,
+	teams []tournament.Team,
+	availableCourts int,
+	courtWeights tournament.CourtWeights,
+	history *tournament.PairingHistory,
+) (tournament.Tournament, error) {
+
+	if t == nil {
+		return nil, errors.New("unknown tournament type")
+	}
+
+	switch t := t.(type) {
+	case *tournament.Rodeo:
+		options := t.Options
+		if availableCourts > 0 {
+			options.AvailableCourts = availableCourts
+		}
+		if options.AvailableCourts <= 0 {
+			options.AvailableCourts = busiestRound(t.Rounds)
+		}
+		if len(courtWeights) > 0 {
+			options.CourtWeights = courtWeights
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		rodeoFactory := tournament.NewRodeoFactoryWithOptions(len(t.Rounds), options, history)
+		rodeo, err := rodeoFactory.Reschedule(ctx, t, fromRound, teams)
+		if err != nil {
+			return nil, err
+		}
+		return rodeo, nil
+	case *tournament.Mexicano:
+		if availableCourts <= 0 {
+			availableCourts = busiestRound(t.Rounds)
+		}
+		mexicanoFactory := tournament.MexicanoFactory{
+			AvailableCourts: availableCourts,
+		}
+		mexicano, err := mexicanoFactory.Reschedule(t, fromRound, teams)
+		if err != nil {
+			return nil, err
+		}
+		return mexicano, nil
+	default:
+		return nil, fmt.Errorf("tournament %s does not support rescheduling", t.GetName())
+	}
+}
+
+func busiestRound(rounds []tournament.Round) int {
+	res := 0
+	for _, round := range rounds {
+		res = max(res, len(round.Matches))
+	}
+	return res
+}
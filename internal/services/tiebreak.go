@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/strang3nt/padel-services/internal/tournament"
+)
+
+// MakeTieBreak generates the playoff rounds deciding the ties at the given
+// positions of a completed rodeo. When availableCourts is not positive, the
+// courts used by the last round are assumed to be available.
+func MakeTieBreak(
+	t tournament.Tournament,
+	availableCourts int,
+	positions []int,
+	courtWeights tournament.CourtWeights,
+) ([]tournament.Round, error) {
+
+	if t == nil {
+		return nil, errors.New("unknown tournament type")
+	}
+
+	if availableCourts <= 0 {
+		rounds := t.GetRounds()
+		if len(rounds) > 0 {
+			availableCourts = len(rounds[len(rounds)-1].Matches)
+		}
+	}
+
+	tieBreakFactory := tournament.NewTieBreakFactory(availableCourts, positions)
+	tieBreakFactory.CourtWeights = courtWeights
+	return tieBreakFactory.MakeTieBreak(t)
+}
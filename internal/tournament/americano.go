@@ -0,0 +1,154 @@
+package tournament
+
+import (
+	"sort"
+	"time"
+)
+
+// Americano is a single player tournament where partners rotate every round
+// and each player collects the points scored by the teams they played in.
+type Americano struct {
+	Name      string
+	DateStart time.Time
+	Teams     []Team
+	Rounds    []Round
+}
+
+func (americano *Americano) GetName() string {
+	return americano.Name
+}
+
+func (americano *Americano) GetDateStart() time.Time {
+	return americano.DateStart
+}
+
+func (americano *Americano) GetTeams() []Team {
+	return americano.Teams
+}
+
+func (americano *Americano) GetRounds() []Round {
+	return americano.Rounds
+}
+
+func NewAmericano(
+	name string,
+	dateStart time.Time,
+	teams []Team,
+	rounds []Round,
+) *Americano {
+	return &Americano{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeAmericano(
+	name string,
+	dateStart time.Time,
+	teams []Team,
+	rounds []Round,
+) Americano {
+	return Americano{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (americano *Americano) GetTournamentType() TournamentType {
+	return TournamentTypeAmericano
+}
+
+func (americano Americano) GetResting(round int, separator string) []string {
+	if round > len(americano.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	people := make(map[string]any)
+	for _, p := range GetPeople(americano.Teams) {
+		people[p.Id] = struct{}{}
+	}
+
+	for _, m := range americano.Rounds[round].Matches {
+		delete(people, m.TeamA.Person1.Id)
+		delete(people, m.TeamA.Person2.Id)
+		delete(people, m.TeamB.Person1.Id)
+		delete(people, m.TeamB.Person2.Id)
+	}
+
+	res := make([]string, 0)
+	for id := range people {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetStandings returns the players ranked by the points they scored.
+func (americano *Americano) GetStandings() []PlayerStanding {
+	return GetPlayerStandings(GetPeople(americano.Teams), americano.Rounds)
+}
+
+type PlayerStanding struct {
+	Player Person `json:"player"`
+	Points int    `json:"points"`
+	Played int    `json:"played"`
+}
+
+// GetPlayerStandings sums, for every person, the points scored by the teams
+// they played in. Only completed matches count. Players with the same points
+// are ranked by fewer matches played, then by name.
+func GetPlayerStandings(people []Person, rounds []Round) []PlayerStanding {
+	standings := make(map[string]*PlayerStanding)
+	for _, p := range people {
+		if _, ok := standings[p.Id]; !ok {
+			standings[p.Id] = &PlayerStanding{Player: p}
+		}
+	}
+
+	addPoints := func(t *Team, points int) {
+		for _, p := range []Person{t.Person1, t.Person2} {
+			if p.IsNil() {
+				continue
+			}
+			s, ok := standings[p.Id]
+			if !ok {
+				s = &PlayerStanding{Player: p}
+				standings[p.Id] = s
+			}
+			s.Points += points
+			s.Played += 1
+		}
+	}
+
+	for _, round := range rounds {
+		for _, m := range round.Matches {
+			if m.MatchStatus != MatchCompleted {
+				continue
+			}
+			addPoints(m.TeamA, m.ScoreA)
+			addPoints(m.TeamB, m.ScoreB)
+		}
+	}
+
+	res := make([]PlayerStanding, 0, len(standings))
+	for _, s := range standings {
+		res = append(res, *s)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Points != res[j].Points {
+			return res[i].Points > res[j].Points
+		}
+		if res[i].Played != res[j].Played {
+			return res[i].Played < res[j].Played
+		}
+		return res[i].Player.Id < res[j].Player.Id
+	})
+
+	return res
+}
@@ -0,0 +1,276 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+)
+
+type AmericanoFactory struct {
+	Name            string
+	MaxRounds       int
+	AvailableCourts int
+	// People are the players, by their Id.
+	People map[string]Person
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewAmericanoFactory(
+	turns int,
+	participants map[string]Person,
+	availableCourts int,
+) *AmericanoFactory {
+	return &AmericanoFactory{
+		MaxRounds:       turns,
+		AvailableCourts: availableCourts,
+		People:          participants,
+	}
+}
+
+// MakeTournament builds an Americano where partnerships come from a round
+// robin between the players (circle method), so that nobody partners the same
+// player twice. When there are not enough courts for everybody, whole
+// partnerships sit out so that rests are spread as evenly as possible. The
+// partnerships that play are then paired against each other, preferring
+// opponents that met the least so far.
+func (af *AmericanoFactory) MakeTournament(
+	name string,
+	dateStart time.Time,
+) (*Americano, error) {
+
+	people := sortPeople(af.People)
+	n := len(people)
+
+	courts := min(af.AvailableCourts, n/4)
+	if courts <= 0 || af.MaxRounds <= 0 {
+		return nil, errors.New(
+			"could not determine valid match parameters. Returning empty tournament",
+		)
+	}
+
+	partnerRounds := circleMethod(n)
+	if af.MaxRounds > len(partnerRounds) {
+		return nil, fmt.Errorf(
+			"%d players can play at most %d rounds without repeating a partner, got %d",
+			n,
+			len(partnerRounds),
+			af.MaxRounds,
+		)
+	}
+
+	playingPairs := chooseAmericanoPlayingPairs(partnerRounds[:af.MaxRounds], n, courts)
+
+	opponents := make([][]int, n)
+	for i := range opponents {
+		opponents[i] = make([]int, n)
+	}
+
+	var teams []Team
+	var turns []Round
+	for _, pairs := range playingPairs {
+
+		var matches []Match
+		currCourt := 1
+		for _, pairing := range pairAmericanoTeams(pairs, opponents) {
+			a, b := pairing[0], pairing[1]
+
+			teamA := MakeTeam(people[a.P1], people[a.P2], Else)
+			teamB := MakeTeam(people[b.P1], people[b.P2], Else)
+			teams = append(teams, teamA, teamB)
+
+			matches = append(matches, Match{
+				TeamA:   &teamA,
+				TeamB:   &teamB,
+				CourtId: currCourt,
+			})
+			currCourt += 1
+
+			for _, x := range []Node{a.P1, a.P2} {
+				for _, y := range []Node{b.P1, b.P2} {
+					opponents[x][y] += 1
+					opponents[y][x] += 1
+				}
+			}
+		}
+
+		turns = append(turns, Round{Matches: matches})
+	}
+
+	balanceCourts(turns, 0, af.CourtWeights)
+
+	return NewAmericano(name, dateStart, teams, turns), nil
+}
+
+// Chooses, for every round, which partnerships play when the courts can not
+// host all of them. Since partnerships rest as a whole, a player's rests depend
+// on who they are paired with: the choice is built greedily (partnerships whose
+// players rested the most play first) and then improved by swapping a resting
+// partnership with a playing one of the same round, as long as the rests get
+// closer to each other. The greedy start is repeated with shuffled ties and the
+// most balanced outcome is kept.
+func chooseAmericanoPlayingPairs(partnerRounds [][]edge, n int, courts int) [][]edge {
+	var best [][]edge
+	bestScore := [2]int{math.MaxInt, math.MaxInt}
+
+	random := rand.New(rand.NewPCG(uint64(n), uint64(courts)))
+	for attempt := range americanoRestAttempts {
+		playing, rests := chooseAmericanoPlayingPairsOnce(partnerRounds, n, courts, random, attempt == 0)
+		score := restsScore(rests)
+		if score[0] < bestScore[0] || (score[0] == bestScore[0] && score[1] < bestScore[1]) {
+			best = playing
+			bestScore = score
+		}
+		if bestScore[0] <= 1 {
+			break
+		}
+	}
+
+	return best
+}
+
+const americanoRestAttempts = 50
+
+// Returns the gap between the most and least rested players, followed by the
+// sum of the squared rests: the lower the better.
+func restsScore(rests []int) [2]int {
+	minRests, maxRests, squares := math.MaxInt, 0, 0
+	for _, r := range rests {
+		minRests = min(minRests, r)
+		maxRests = max(maxRests, r)
+		squares += r * r
+	}
+	return [2]int{maxRests - minRests, squares}
+}
+
+func chooseAmericanoPlayingPairsOnce(
+	partnerRounds [][]edge,
+	n int,
+	courts int,
+	random *rand.Rand,
+	deterministic bool,
+) ([][]edge, []int) {
+	rests := make([]int, n)
+	playing := make([][]edge, len(partnerRounds))
+	resting := make([][]edge, len(partnerRounds))
+
+	for r, round := range partnerRounds {
+		pairs := make([]edge, len(round))
+		copy(pairs, round)
+		if !deterministic {
+			random.Shuffle(len(pairs), func(i, j int) {
+				pairs[i], pairs[j] = pairs[j], pairs[i]
+			})
+		}
+
+		// Players with the most rests so far are the first ones to play.
+		sort.SliceStable(pairs, func(i, j int) bool {
+			return rests[pairs[i].P1]+rests[pairs[i].P2] >
+				rests[pairs[j].P1]+rests[pairs[j].P2]
+		})
+
+		playingPairs := min(2*courts, len(pairs))
+		playingPairs -= playingPairs % 2
+		playing[r] = pairs[:playingPairs]
+		resting[r] = pairs[playingPairs:]
+
+		playingNodes := make(nodeSet)
+		for _, p := range playing[r] {
+			playingNodes[int(p.P1)] = struct{}{}
+			playingNodes[int(p.P2)] = struct{}{}
+		}
+		for i := range n {
+			if !playingNodes.contains(i) {
+				rests[i] += 1
+			}
+		}
+	}
+
+	improved := true
+	for improved {
+		improved = false
+		for r := range partnerRounds {
+			for i := range resting[r] {
+				for j := range playing[r] {
+					rest, play := resting[r][i], playing[r][j]
+					before := restsScore(rests)
+
+					rests[rest.P1] -= 1
+					rests[rest.P2] -= 1
+					rests[play.P1] += 1
+					rests[play.P2] += 1
+
+					after := restsScore(rests)
+					if after[0] < before[0] || (after[0] == before[0] && after[1] < before[1]) {
+						resting[r][i], playing[r][j] = play, rest
+						improved = true
+						continue
+					}
+
+					rests[rest.P1] += 1
+					rests[rest.P2] += 1
+					rests[play.P1] -= 1
+					rests[play.P2] -= 1
+				}
+			}
+		}
+	}
+
+	return playing, rests
+}
+
+// Pairs partnerships against each other, picking for each one the opponent
+// partnership whose players it faced the fewest times.
+func pairAmericanoTeams(pairs []edge, opponents [][]int) [][2]edge {
+	res := make([][2]edge, 0, len(pairs)/2)
+	used := make([]bool, len(pairs))
+
+	for i := range pairs {
+		if used[i] {
+			continue
+		}
+		used[i] = true
+
+		best := -1
+		bestCost := 0
+		for j := i + 1; j < len(pairs); j++ {
+			if used[j] {
+				continue
+			}
+
+			cost := 0
+			for _, x := range []Node{pairs[i].P1, pairs[i].P2} {
+				for _, y := range []Node{pairs[j].P1, pairs[j].P2} {
+					cost += opponents[x][y]
+				}
+			}
+
+			if best == -1 || cost < bestCost {
+				best = j
+				bestCost = cost
+			}
+		}
+
+		if best == -1 {
+			break
+		}
+		used[best] = true
+		res = append(res, [2]edge{pairs[i], pairs[best]})
+	}
+
+	return res
+}
+
+func sortPeople(people map[string]Person) []Person {
+	res := make([]Person, 0, len(people))
+	for _, p := range people {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Id < res[j].Id
+	})
+	return res
+}
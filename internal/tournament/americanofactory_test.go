@@ -0,0 +1,153 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func makePeople(n int) map[string]Person {
+	people := make(map[string]Person)
+	for i := range n {
+		people[fmt.Sprintf("Player%02d", i)] = Person{Id: fmt.Sprintf("Player%02d", i)}
+	}
+	return people
+}
+
+func TestMakeAmericano8People(t *testing.T) {
+
+	americanoFactory := AmericanoFactory{
+		MaxRounds:       7,
+		AvailableCourts: 2,
+		People:          makePeople(8),
+	}
+
+	americano, err := americanoFactory.MakeTournament("americano", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building americano: %v", err)
+	}
+
+	t.Run("Assertion_1_EveryoneAlwaysPlays", func(t *testing.T) {
+		for i, round := range americano.GetRounds() {
+			if len(round.Matches) != 2 {
+				t.Errorf("Round %d: expected 2 matches, got %d", i+1, len(round.Matches))
+			}
+			if resting := americano.GetResting(i, "-"); len(resting) != 0 {
+				t.Errorf("Round %d: expected nobody resting, got %v", i+1, resting)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_NoRepeatedPartners", func(t *testing.T) {
+		partners := make(map[[2]string]int)
+		for _, round := range americano.GetRounds() {
+			for _, m := range round.Matches {
+				for _, team := range []*Team{m.TeamA, m.TeamB} {
+					partners[[2]string{team.Person1.Id, team.Person2.Id}] += 1
+				}
+			}
+		}
+
+		if len(partners) != 28 {
+			t.Errorf("expected every one of the 28 partnerships once, got %d", len(partners))
+		}
+		for pair, count := range partners {
+			if count != 1 {
+				t.Errorf("players %v partnered %d times", pair, count)
+			}
+		}
+	})
+}
+
+func TestMakeAmericanoWithRests(t *testing.T) {
+
+	americanoFactory := AmericanoFactory{
+		MaxRounds:       9,
+		AvailableCourts: 2,
+		People:          makePeople(10),
+	}
+
+	americano, err := americanoFactory.MakeTournament("americano", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building americano: %v", err)
+	}
+
+	t.Run("Assertion_1_RestsAreSpreadEvenly", func(t *testing.T) {
+		rests := make(map[string]int)
+		for i := range americano.GetRounds() {
+			for _, p := range americano.GetResting(i, "-") {
+				rests[p] += 1
+			}
+		}
+
+		minRests, maxRests := 9, 0
+		for _, p := range makePeople(10) {
+			minRests = min(minRests, rests[p.Id])
+			maxRests = max(maxRests, rests[p.Id])
+		}
+		if maxRests-minRests > 1 {
+			t.Errorf("rests are unbalanced: min %d, max %d (%v)", minRests, maxRests, rests)
+		}
+	})
+
+	t.Run("Assertion_2_TooManyRounds", func(t *testing.T) {
+		americanoFactory.MaxRounds = 10
+		if _, err := americanoFactory.MakeTournament("americano", time.Now()); err == nil {
+			t.Errorf("expected an error when partners would repeat")
+		}
+	})
+}
+
+func TestAmericanoStandings(t *testing.T) {
+	p1 := Person{Id: "P1"}
+	p2 := Person{Id: "P2"}
+	p3 := Person{Id: "P3"}
+	p4 := Person{Id: "P4"}
+
+	teamA := Team{Person1: p1, Person2: p2}
+	teamB := Team{Person1: p3, Person2: p4}
+	teamC := Team{Person1: p1, Person2: p3}
+	teamD := Team{Person1: p2, Person2: p4}
+
+	americano := MakeAmericano("americano", time.Now(), []Team{teamA, teamB, teamC, teamD}, []Round{
+		{Matches: []Match{
+			{TeamA: &teamA, TeamB: &teamB, MatchStatus: MatchCompleted, ScoreA: 15, ScoreB: 9},
+		}},
+		{Matches: []Match{
+			{TeamA: &teamC, TeamB: &teamD, MatchStatus: MatchCompleted, ScoreA: 10, ScoreB: 14},
+		}},
+	})
+
+	standings := americano.GetStandings()
+	expected := []PlayerStanding{
+		{Player: p2, Points: 29, Played: 2},
+		{Player: p1, Points: 25, Played: 2},
+		{Player: p4, Points: 23, Played: 2},
+		{Player: p3, Points: 19, Played: 2},
+	}
+
+	for i := range expected {
+		if standings[i] != expected[i] {
+			t.Errorf("position %d: expected %v, got %v", i+1, expected[i], standings[i])
+		}
+	}
+}
+
+func TestPlayerStandingsWithStoredPeople(t *testing.T) {
+	// People are rated when the tournament is made, but the stored matches
+	// only know their Ids.
+	rated := []Person{{Id: "P1", Rating: 4}, {Id: "P2", Rating: 3}, {Id: "P3", Rating: 2}, {Id: "P4", Rating: 1}}
+	teamA := Team{Person1: Person{Id: "P1"}, Person2: Person{Id: "P2"}}
+	teamB := Team{Person1: Person{Id: "P3"}, Person2: Person{Id: "P4"}}
+	rounds := []Round{{Matches: []Match{
+		{TeamA: &teamA, TeamB: &teamB, MatchStatus: MatchCompleted, ScoreA: 15, ScoreB: 9},
+	}}}
+
+	standings := GetPlayerStandings(rated, rounds)
+	if len(standings) != 4 {
+		t.Fatalf("expected 4 players, got %v", standings)
+	}
+	if standings[0].Player != rated[0] || standings[0].Points != 15 || standings[0].Played != 1 {
+		t.Errorf("expected %s first with 15 points in 1 match, got %v", rated[0].Id, standings[0])
+	}
+}
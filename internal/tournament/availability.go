@@ -0,0 +1,204 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+)
+
+// Number of schedules tried for every number of matches per participant,
+// before settling for fewer matches.
+const availabilityAttempts = 200
+
+// Availability is the window of rounds in which a person is present, e.g. a
+// person arriving late and leaving early. Rounds are numbered from 1 and the
+// window includes FirstRound and LastRound; a zero FirstRound or LastRound
+// means that the person is present from the first or until the last round.
+type Availability struct {
+	Person     Person `json:"person"`
+	FirstRound int    `json:"firstRound"`
+	LastRound  int    `json:"lastRound"`
+}
+
+// IsPresent tells whether the person is present in the 0-based round.
+func (a Availability) IsPresent(round int) bool {
+	if a.FirstRound > 0 && round+1 < a.FirstRound {
+		return false
+	}
+	if a.LastRound > 0 && round+1 > a.LastRound {
+		return false
+	}
+	return true
+}
+
+func validateAvailability(availability []Availability, roundsNumber int) error {
+	seen := make(map[string]any)
+	for _, a := range availability {
+		if _, ok := seen[a.Person.Id]; ok {
+			return fmt.Errorf("%s has more than one availability window", a.Person.Id)
+		}
+		seen[a.Person.Id] = struct{}{}
+
+		if a.FirstRound < 0 || a.LastRound < 0 ||
+			(a.LastRound > 0 && a.LastRound < a.FirstRound) ||
+			a.FirstRound > roundsNumber {
+			return fmt.Errorf(
+				"%s is never present between round 1 and round %d",
+				a.Person.Id,
+				roundsNumber,
+			)
+		}
+	}
+	return nil
+}
+
+// Returns, for every group of people, in which rounds all of them are
+// present. People without an availability window are always present. People
+// are matched by their Id.
+func getPresence(availability []Availability, groups [][]Person, roundsNumber int) [][]bool {
+	windows := make(map[string]Availability)
+	for _, a := range availability {
+		windows[a.Person.Id] = a
+	}
+
+	res := make([][]bool, len(groups))
+	for i, people := range groups {
+		res[i] = make([]bool, roundsNumber)
+		for r := range roundsNumber {
+			res[i][r] = true
+			for _, p := range people {
+				if w, ok := windows[p.Id]; ok && !w.IsPresent(r) {
+					res[i][r] = false
+				}
+			}
+		}
+	}
+	return res
+}
+
+// availabilityPlanner keeps track of the matches played by the participants,
+// teams or people, while a schedule is built round by round.
+type availabilityPlanner struct {
+	present [][]bool
+	target  []int
+	played  []int
+	random  *rand.Rand
+}
+
+// Every participant aims at matchesPerParticipant matches, or at one match
+// per round in which it is present when it is present for fewer rounds.
+func newAvailabilityPlanner(present [][]bool, matchesPerParticipant int, random *rand.Rand) *availabilityPlanner {
+	target := make([]int, len(present))
+	for i, rounds := range present {
+		presentRounds := 0
+		for _, p := range rounds {
+			if p {
+				presentRounds++
+			}
+		}
+		target[i] = min(matchesPerParticipant, presentRounds)
+	}
+
+	return &availabilityPlanner{
+		present: present,
+		target:  target,
+		played:  make([]int, len(present)),
+		random:  random,
+	}
+}
+
+// Returns the participants present in the round that still need matches,
+// those with the most matches left per remaining round first. Ties are
+// broken at random, so that every attempt builds a different schedule.
+func (ap *availabilityPlanner) order(round int) []int {
+	var res []int
+	urgency := make(map[int]float64)
+	for i := range ap.present {
+		if !ap.present[i][round] || ap.played[i] >= ap.target[i] {
+			continue
+		}
+		remainingRounds := 0
+		for _, p := range ap.present[i][round:] {
+			if p {
+				remainingRounds++
+			}
+		}
+		res = append(res, i)
+		urgency[i] = float64(ap.target[i]-ap.played[i]) / float64(remainingRounds)
+	}
+
+	ap.random.Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	sort.SliceStable(res, func(i, j int) bool { return urgency[res[i]] > urgency[res[j]] })
+	return res
+}
+
+// Returns how many participants play fewer matches than their target, and
+// whether every participant is at most one match short of it.
+func (ap *availabilityPlanner) shortfall() (int, bool) {
+	short := 0
+	for i := range ap.played {
+		if ap.played[i] < ap.target[i]-1 {
+			return 0, false
+		}
+		if ap.played[i] < ap.target[i] {
+			short++
+		}
+	}
+	return short, true
+}
+
+// Looks for the schedule that gives every participant as many matches as
+// possible, as equal as possible: starting from matchesPerParticipant, every
+// participant must be at most one match short of its target. build makes one
+// attempt with the given planner and returns its schedule.
+func planWithAvailability[S any](
+	ctx context.Context,
+	present [][]bool,
+	matchesPerParticipant int,
+	build func(*availabilityPlanner) S,
+) (S, *availabilityPlanner, error) {
+	var best S
+	var bestPlanner *availabilityPlanner
+
+	for m := matchesPerParticipant; m > 0; m-- {
+		bestShort := -1
+		for attempt := range availabilityAttempts {
+			if err := ctx.Err(); err != nil {
+				var zero S
+				return zero, nil, err
+			}
+
+			planner := newAvailabilityPlanner(
+				present,
+				m,
+				rand.New(rand.NewPCG(uint64(m), uint64(attempt))),
+			)
+			schedule := build(planner)
+
+			short, ok := planner.shortfall()
+			if ok && (bestShort < 0 || short < bestShort) {
+				best, bestPlanner, bestShort = schedule, planner, short
+			}
+			if bestShort == 0 {
+				break
+			}
+		}
+		if bestShort >= 0 {
+			return best, bestPlanner, nil
+		}
+	}
+
+	var zero S
+	return zero, nil, fmt.Errorf("could not schedule the matches with the given availability")
+}
+
+// Returns the fewest and the most matches every participant may play: its
+// target, or one match less.
+func (ap *availabilityPlanner) bounds() ([]int, []int) {
+	minMatches := make([]int, len(ap.target))
+	for i, t := range ap.target {
+		minMatches[i] = max(t-1, 0)
+	}
+	return minMatches, ap.target
+}
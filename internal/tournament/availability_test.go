@@ -0,0 +1,121 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMakeTournamentWithAvailability(t *testing.T) {
+	teams := makeTeams(8)
+	dateStart := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+
+	// Team00 arrives at round 3, Team01 leaves after round 6.
+	availability := []Availability{
+		{Person: teams[0].Person1, FirstRound: 3},
+		{Person: teams[1].Person2, LastRound: 6},
+	}
+	rodeoFactory := RodeoFactory{
+		MaxRounds:       8,
+		AvailableCourts: 4,
+		Availability:    availability,
+	}
+	rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+
+	played := make(map[Team]int)
+	for i, round := range rodeo.GetRounds() {
+		for _, m := range round.Matches {
+			played[*m.TeamA]++
+			played[*m.TeamB]++
+			for _, team := range []Team{*m.TeamA, *m.TeamB} {
+				if (team == teams[0] && i < 2) || (team == teams[1] && i > 5) {
+					t.Errorf("Round %d: %v plays when not present", i+1, team)
+				}
+			}
+		}
+	}
+
+	t.Run("Assertion_1_PlaysOnlyWhenPresent", func(t *testing.T) {
+		if played[teams[0]] > 6 || played[teams[1]] > 6 {
+			t.Errorf("expected late and early teams to play at most 6 matches, got %d and %d",
+				played[teams[0]], played[teams[1]])
+		}
+	})
+
+	t.Run("Assertion_2_MatchCountsAsEqualAsPossible", func(t *testing.T) {
+		fewest, most := len(teams), 0
+		for _, team := range teams {
+			fewest = min(fewest, played[team])
+			most = max(most, played[team])
+		}
+		if most-fewest > 1 {
+			t.Errorf("expected match counts within one match, got from %d to %d", fewest, most)
+		}
+	})
+
+	t.Run("Assertion_3_NeverPresent", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{
+			MaxRounds:       8,
+			AvailableCourts: 4,
+			Availability:    []Availability{{Person: teams[0].Person1, FirstRound: 9}},
+		}
+		if _, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart); err == nil {
+			t.Errorf("expected an error when a person is never present")
+		}
+	})
+}
+
+func TestMakeSinglePlayerRodeoWithAvailability(t *testing.T) {
+	people := make(map[string]Person)
+	for i := range 12 {
+		people[fmt.Sprintf("P%02d", i)] = Person{Id: fmt.Sprintf("P%02d", i)}
+	}
+	late := Person{Id: "P00"}
+
+	factory := SinglePlayerRodeoFactory{
+		MaxRounds:       6,
+		AvailableCourts: 3,
+		People:          people,
+		Availability:    []Availability{{Person: late, FirstRound: 4}},
+	}
+	rodeo, err := factory.MakeTournament(context.Background(), "rodeo", time.Now())
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+
+	played := make(map[Person]int)
+	for i, round := range rodeo.GetRounds() {
+		for _, m := range round.Matches {
+			for _, p := range []Person{m.TeamA.Person1, m.TeamA.Person2, m.TeamB.Person1, m.TeamB.Person2} {
+				played[p]++
+				if p == late && i < 3 {
+					t.Errorf("Round %d: %s plays before arriving", i+1, p.Id)
+				}
+			}
+		}
+	}
+
+	t.Run("Assertion_1_LateArrivalPlaysEveryRemainingRound", func(t *testing.T) {
+		if played[late] < 2 {
+			t.Errorf("expected %s to play at least 2 of the last 3 rounds, got %d", late.Id, played[late])
+		}
+	})
+
+	t.Run("Assertion_2_OthersPlayTheSame", func(t *testing.T) {
+		fewest, most := 6, 0
+		for _, p := range people {
+			if p == late {
+				continue
+			}
+			fewest = min(fewest, played[p])
+			most = max(most, played[p])
+		}
+		if most-fewest > 1 {
+			t.Errorf("expected match counts within one match, got from %d to %d", fewest, most)
+		}
+	})
+}
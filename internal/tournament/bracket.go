@@ -0,0 +1,404 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// BracketMatch is a match of an elimination bracket. Teams are nil while they
+// are not known yet, SourceA and SourceB may then tell where they will come
+// from. Bye tells that TeamA advances without playing, Match is set once the
+// match has been scheduled.
+type BracketMatch struct {
+	TeamA   *Team
+	TeamB   *Team
+	SourceA string
+	SourceB string
+	Bye     bool
+	Match   *Match
+}
+
+// Returns, for a bracket with the given number of slots (a power of two), the
+// seed placed on each slot, so that the best seeds meet as late as possible:
+// with 8 slots the first round is 1-8, 4-5, 2-7, 3-6.
+func bracketSeeds(size int) []int {
+	seeds := []int{0}
+	for len(seeds) < size {
+		n := len(seeds) * 2
+		next := make([]int, 0, n)
+		for _, s := range seeds {
+			next = append(next, s, n-1-s)
+		}
+		seeds = next
+	}
+	return seeds
+}
+
+// Places the seeded teams on the slots of the bracket's first round, nil marks
+// an empty slot: the team paired with it advances with a bye.
+func makeBracketEntrants(seeded []*Team) []*Team {
+	size := 1
+	for size < len(seeded) {
+		size *= 2
+	}
+
+	entrants := make([]*Team, size)
+	for i, seed := range bracketSeeds(size) {
+		if seed < len(seeded) {
+			entrants[i] = seeded[seed]
+		}
+	}
+	return entrants
+}
+
+func findMatch(round *Round, a *Team, b *Team) *Match {
+	for i := range round.Matches {
+		m := &round.Matches[i]
+		if (*m.TeamA == *a && *m.TeamB == *b) || (*m.TeamA == *b && *m.TeamB == *a) {
+			return m
+		}
+	}
+	return nil
+}
+
+// Returns the teams advancing from a bracket round, nil when the winner is
+// not known yet. In the first round, empty slots are byes.
+func advanceBracket(entrants []*Team, round *Round, firstRound bool) []*Team {
+	next := make([]*Team, len(entrants)/2)
+
+	for i := range next {
+		a, b := entrants[2*i], entrants[2*i+1]
+
+		switch {
+		case firstRound && a == nil:
+			next[i] = b
+		case firstRound && b == nil:
+			next[i] = a
+		case a == nil || b == nil || round == nil:
+			next[i] = nil
+		default:
+			if m := findMatch(round, a, b); m != nil {
+				next[i] = m.Winner()
+			}
+		}
+	}
+
+	return next
+}
+
+// Returns the pairs of a bracket round that both teams are known for, and
+// that have no match in the round yet.
+func unscheduledPairs(entrants []*Team, round *Round) [][2]*Team {
+	var res [][2]*Team
+	for i := 0; i+1 < len(entrants); i += 2 {
+		a, b := entrants[i], entrants[i+1]
+		if a == nil || b == nil || (round != nil && findMatch(round, a, b) != nil) {
+			continue
+		}
+		res = append(res, [2]*Team{a, b})
+	}
+	return res
+}
+
+// Groups the rounds played so far by the bracket round they belong to. A
+// bracket round with more matches than courts is played over consecutive
+// rounds.
+func groupBracketRounds(entrants []*Team, rounds []Round) []Round {
+	var res []Round
+
+	current := entrants
+	for next := 0; next < len(rounds) && len(current) > 1; {
+		expected := len(unscheduledPairs(current, nil))
+		if expected == 0 {
+			break
+		}
+		group := Round{Date: rounds[next].Date}
+		for next < len(rounds) && len(group.Matches) < expected {
+			group.Matches = append(group.Matches, rounds[next].Matches...)
+			next++
+		}
+		res = append(res, group)
+
+		current = advanceBracket(current, &group, len(res) == 1)
+	}
+
+	return res
+}
+
+// Computes the whole bracket, from the first round to the final, filling in
+// the teams and the matches known so far. Teams not known yet are sourced
+// from the match of the previous round they come from, e.g. "Vincente 1.2"
+// for the winner of the second match of the first round.
+func makeBracket(entrants []*Team, rounds []Round) [][]BracketMatch {
+	rounds = groupBracketRounds(entrants, rounds)
+	var res [][]BracketMatch
+
+	current := entrants
+	for r := 0; len(current) > 1; r++ {
+		var round *Round
+		if r < len(rounds) {
+			round = &rounds[r]
+		}
+
+		bracketRound := make([]BracketMatch, 0, len(current)/2)
+		for i := 0; i+1 < len(current); i += 2 {
+			bm := BracketMatch{TeamA: current[i], TeamB: current[i+1]}
+			if r > 0 {
+				bm.SourceA = fmt.Sprintf("Vincente %d.%d", r, i+1)
+				bm.SourceB = fmt.Sprintf("Vincente %d.%d", r, i+2)
+			}
+
+			if r == 0 && (bm.TeamA == nil || bm.TeamB == nil) {
+				if bm.TeamA == nil {
+					bm.TeamA, bm.TeamB = bm.TeamB, bm.TeamA
+				}
+				bm.Bye = true
+			} else if round != nil && bm.TeamA != nil && bm.TeamB != nil {
+				bm.Match = findMatch(round, bm.TeamA, bm.TeamB)
+			}
+
+			bracketRound = append(bracketRound, bm)
+		}
+		res = append(res, bracketRound)
+
+		current = advanceBracket(current, round, r == 0)
+	}
+
+	return res
+}
+
+// Makes a round of the pairs, one match per court. The pairs that do not fit
+// on the courts are played in the following rounds.
+func makeBracketRound(pairs [][2]*Team, availableCourts int) Round {
+	var matches []Match
+
+	for _, pair := range pairs[:min(len(pairs), availableCourts)] {
+		matches = append(matches, Match{
+			TeamA:   pair[0],
+			TeamB:   pair[1],
+			CourtId: len(matches) + 1,
+		})
+	}
+
+	return Round{Matches: matches}
+}
+
+var errBracketOver = errors.New("the bracket is over, there are no more rounds to play")
+
+// Generates the next round of the bracket from the winners of the rounds
+// played so far.
+func nextBracketRound(entrants []*Team, rounds []Round, availableCourts int) (Round, error) {
+	if availableCourts <= 0 {
+		return Round{}, errors.New("at least one court is needed to generate a round")
+	}
+
+	current := entrants
+	rounds = groupBracketRounds(entrants, rounds)
+	for r := range rounds {
+		if pairs := unscheduledPairs(current, &rounds[r]); len(pairs) > 0 {
+			return makeBracketRound(pairs, availableCourts), nil
+		}
+		next := advanceBracket(current, &rounds[r], r == 0)
+		for _, t := range next {
+			if t == nil {
+				return Round{}, fmt.Errorf(
+					"round %d is not over: some matches have no winner yet",
+					r+1,
+				)
+			}
+		}
+		current = next
+	}
+
+	if len(current) <= 1 {
+		return Round{}, errBracketOver
+	}
+
+	return makeBracketRound(unscheduledPairs(current, nil), availableCourts), nil
+}
+
+// EliminationOptions are the extra brackets of an elimination tournament: a
+// consolation bracket for the teams losing their first match, and a match
+// for the third place between the semifinal losers.
+type EliminationOptions struct {
+	Consolation bool `json:"consolation"`
+	ThirdPlace  bool `json:"thirdPlace"`
+}
+
+// Returns the rounds keeping only the matches of the given stages, rounds
+// with no such match are dropped.
+func stageRounds(rounds []Round, stages ...MatchStage) []Round {
+	var res []Round
+	for _, r := range rounds {
+		var matches []Match
+		for _, m := range r.Matches {
+			if slices.Contains(stages, m.Stage) {
+				matches = append(matches, m)
+			}
+		}
+		if len(matches) > 0 {
+			res = append(res, Round{Matches: matches, Date: r.Date})
+		}
+	}
+	return res
+}
+
+// Returns the teams that lost their first match of the main bracket, sorted
+// by seed, and whether they are all known. Teams with a bye in the first
+// round play their first match in the second one.
+func consolationEntrants(seeded []*Team, mainRounds []Round) ([]*Team, bool) {
+	bracket := makeBracket(makeBracketEntrants(seeded), mainRounds)
+	if len(bracket) == 0 {
+		return nil, false
+	}
+
+	byes := make(map[Team]bool)
+	for _, bm := range bracket[0] {
+		if bm.Bye {
+			byes[*bm.TeamA] = true
+		}
+	}
+
+	lost := make(map[Team]bool)
+	for r := 0; r < len(bracket) && r < 2; r++ {
+		if r == 1 && len(byes) == 0 {
+			break
+		}
+		for _, bm := range bracket[r] {
+			if bm.Bye {
+				continue
+			}
+			if bm.Match == nil || bm.Match.Loser() == nil {
+				return nil, false
+			}
+			loser := bm.Match.Loser()
+			if r == 0 || byes[*loser] {
+				lost[*loser] = true
+			}
+		}
+	}
+
+	var res []*Team
+	for _, t := range seeded {
+		if lost[*t] {
+			res = append(res, t)
+		}
+	}
+	return res, true
+}
+
+// Returns the losers of the semifinals, nil while they are not known or when
+// a semifinal was not played.
+func semifinalLosers(bracket [][]BracketMatch) []*Team {
+	if len(bracket) < 2 {
+		return nil
+	}
+
+	var res []*Team
+	for _, bm := range bracket[len(bracket)-2] {
+		if bm.Bye || bm.Match == nil || bm.Match.Loser() == nil {
+			return nil
+		}
+		res = append(res, bm.Match.Loser())
+	}
+	return res
+}
+
+// Returns the match for the third place, nil when it was not scheduled.
+func thirdPlaceMatch(rounds []Round) *Match {
+	for r := range rounds {
+		for i := range rounds[r].Matches {
+			if rounds[r].Matches[i].Stage == StageThirdPlace {
+				return &rounds[r].Matches[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Generates the next round of an elimination tournament: the next round of
+// the main bracket together with, when enabled, the next round of the
+// consolation bracket and the match for the third place. Every match played
+// so far must be completed.
+func nextEliminationRound(
+	seeded []*Team,
+	rounds []Round,
+	availableCourts int,
+	options EliminationOptions,
+) (Round, error) {
+
+	if availableCourts <= 0 {
+		return Round{}, errors.New("at least one court is needed to generate a round")
+	}
+
+	for r, round := range rounds {
+		for _, m := range round.Matches {
+			if m.MatchStatus != MatchCompleted {
+				return Round{}, fmt.Errorf(
+					"round %d is not over: some matches have no result yet",
+					r+1,
+				)
+			}
+		}
+	}
+
+	var matches []Match
+	addStage := func(round Round, stage MatchStage) {
+		for _, m := range round.Matches {
+			m.Stage = stage
+			matches = append(matches, m)
+		}
+	}
+
+	mainRounds := stageRounds(rounds, StageMain)
+	next, err := nextBracketRound(makeBracketEntrants(seeded), mainRounds, availableCourts)
+	if err != nil && !errors.Is(err, errBracketOver) {
+		return Round{}, err
+	}
+	addStage(next, StageMain)
+
+	if options.ThirdPlace && thirdPlaceMatch(rounds) == nil {
+		bracket := makeBracket(makeBracketEntrants(seeded), mainRounds)
+		if losers := semifinalLosers(bracket); len(losers) == 2 {
+			addStage(Round{Matches: []Match{{TeamA: losers[0], TeamB: losers[1]}}}, StageThirdPlace)
+		}
+	}
+
+	if options.Consolation {
+		losers, known := consolationEntrants(seeded, mainRounds)
+		if known && len(losers) >= 2 {
+			next, err := nextBracketRound(
+				makeBracketEntrants(losers),
+				stageRounds(rounds, StageConsolation),
+				availableCourts,
+			)
+			if err != nil && !errors.Is(err, errBracketOver) {
+				return Round{}, err
+			}
+			addStage(next, StageConsolation)
+		}
+	}
+
+	if len(matches) == 0 {
+		return Round{}, errBracketOver
+	}
+
+	// Matches that do not fit on the courts are generated again with the next
+	// round.
+	matches = matches[:min(len(matches), availableCourts)]
+	for i := range matches {
+		matches[i].CourtId = i + 1
+	}
+
+	return Round{Matches: matches}, nil
+}
+
+// Returns the consolation bracket, nil until the teams playing it are known.
+func makeConsolationBracket(seeded []*Team, rounds []Round) [][]BracketMatch {
+	losers, known := consolationEntrants(seeded, stageRounds(rounds, StageMain))
+	if !known || len(losers) < 2 {
+		return nil
+	}
+	return makeBracket(makeBracketEntrants(losers), stageRounds(rounds, StageConsolation))
+}
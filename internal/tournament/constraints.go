@@ -0,0 +1,279 @@
+package tournament
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+// Number of swaps of edges tried to make a graph of matches follow the
+// constraints.
+const constraintSteps = 10_000
+
+// PersonPair is two people a constraint is about.
+type PersonPair [2]Person
+
+// Constraints are rules given by the organiser that every schedule follows,
+// e.g. for family members, coaches and students or disputes. People are
+// identified by their Id. Two teams face each other when a person of one team
+// faces a person of the other.
+type Constraints struct {
+	// NeverPartners are people that never play in the same team.
+	NeverPartners []PersonPair `json:"neverPartners"`
+	// NeverFace are people that never play against each other.
+	NeverFace []PersonPair `json:"neverFace"`
+	// MustFace are people that play against each other at least once.
+	MustFace []PersonPair `json:"mustFace"`
+}
+
+func containsPair(pairs []PersonPair, a, b Person) bool {
+	return slices.ContainsFunc(pairs, func(p PersonPair) bool {
+		return personPair(p[0], p[1]) == personPair(a, b)
+	})
+}
+
+// Tells whether some person of teamA and some person of teamB are one of
+// the pairs.
+func teamsContainPair(pairs []PersonPair, teamA, teamB Team) bool {
+	for _, a := range teamPeople(teamA) {
+		for _, b := range teamPeople(teamB) {
+			if containsPair(pairs, a, b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c Constraints) canPartner(a, b Person) bool {
+	return !containsPair(c.NeverPartners, a, b)
+}
+
+func (c Constraints) canFace(teamA, teamB Team) bool {
+	return !teamsContainPair(c.NeverFace, teamA, teamB)
+}
+
+func (c Constraints) mustFace(teamA, teamB Team) bool {
+	return teamsContainPair(c.MustFace, teamA, teamB)
+}
+
+// Checks that the constraints do not contradict each other or the teams,
+// that can be nil when partners are not known yet.
+func (c Constraints) validate(teams []Team) error {
+	for _, p := range c.MustFace {
+		if p[0].Id == p[1].Id {
+			return fmt.Errorf("%s cannot face themselves", p[0].Id)
+		}
+		if containsPair(c.NeverFace, p[0], p[1]) {
+			return fmt.Errorf("%s and %s must both face and never face each other", p[0].Id, p[1].Id)
+		}
+	}
+	for _, team := range teams {
+		if !c.canPartner(team.Person1, team.Person2) {
+			return fmt.Errorf(
+				"%s and %s are a team, but must never be partners",
+				team.Person1.Id,
+				team.Person2.Id,
+			)
+		}
+		if containsPair(c.MustFace, team.Person1, team.Person2) {
+			return fmt.Errorf(
+				"%s and %s are a team, so they cannot face each other",
+				team.Person1.Id,
+				team.Person2.Id,
+			)
+		}
+	}
+	return nil
+}
+
+// Returns the constraints without the people that must face each other but
+// are not both in the teams, e.g. because one of them withdrew.
+func (c Constraints) among(teams []Team) Constraints {
+	present := make(map[string]any)
+	for _, team := range teams {
+		for _, p := range teamPeople(team) {
+			present[p.Id] = struct{}{}
+		}
+	}
+	res := c
+	res.MustFace = slices.DeleteFunc(slices.Clone(c.MustFace), func(p PersonPair) bool {
+		_, ok0 := present[p[0].Id]
+		_, ok1 := present[p[1].Id]
+		return !ok0 || !ok1
+	})
+	return res
+}
+
+// Checks that the rounds follow the constraints.
+func (c Constraints) validateRounds(rounds []Round) error {
+	met := make(map[[2]string]any)
+	for r, round := range rounds {
+		for _, m := range round.Matches {
+			for _, team := range []*Team{m.TeamA, m.TeamB} {
+				if !c.canPartner(team.Person1, team.Person2) {
+					return fmt.Errorf("round %d: %s and %s are partners, but must never be",
+						r+1, team.Person1.Id, team.Person2.Id)
+				}
+			}
+			for _, a := range teamPeople(*m.TeamA) {
+				for _, b := range teamPeople(*m.TeamB) {
+					if containsPair(c.NeverFace, a, b) {
+						return fmt.Errorf("round %d: %s faces %s, but must never", r+1, a.Id, b.Id)
+					}
+					met[personPair(a, b)] = struct{}{}
+				}
+			}
+		}
+	}
+	for _, p := range c.MustFace {
+		if _, ok := met[personPair(p[0], p[1])]; !ok {
+			return fmt.Errorf("%s and %s must face each other, but never do", p[0].Id, p[1].Id)
+		}
+	}
+	return nil
+}
+
+// Returns the edges between the teams that must face each other.
+func (c Constraints) requiredEdges(teams []Team) []edge {
+	var res []edge
+	for i := range teams {
+		for j := i + 1; j < len(teams); j++ {
+			if c.mustFace(teams[i], teams[j]) {
+				res = append(res, edge{P1: Node(i), P2: Node(j)})
+			}
+		}
+	}
+	return res
+}
+
+// Makes the graph of matches follow the constraints, by swapping the ends of
+// two edges at a time: (a, b) and (c, d) become (a, c) and (b, d). Every
+// node keeps the number of its edges, so every team keeps its number of
+// matches. Edges for which forbidden holds are swapped out and the required
+// edges are swapped in.
+func rewireMatching(
+	m matching,
+	forbidden func(a, b int) bool,
+	required []edge,
+	random *rand.Rand,
+) (matching, error) {
+
+	res := make(matching)
+	for e := range m {
+		res.addCanonicalEdge(int(e.P1), int(e.P2))
+	}
+	isRequired := make(map[edge]any)
+	for _, e := range required {
+		isRequired[e] = struct{}{}
+	}
+	has := func(a, b int) bool {
+		_, ok := res[edge{P1: Node(min(a, b)), P2: Node(max(a, b))}]
+		return ok
+	}
+	// Swaps are skipped when they would add a loop, an edge twice or a
+	// forbidden edge.
+	swap := func(remove [2]edge, add [2][2]int) {
+		for _, e := range add {
+			if e[0] == e[1] || has(e[0], e[1]) || forbidden(e[0], e[1]) {
+				return
+			}
+		}
+		for _, e := range remove {
+			delete(res, e)
+		}
+		for _, e := range add {
+			res.addCanonicalEdge(e[0], e[1])
+		}
+	}
+
+	for range constraintSteps {
+		// Edges are sorted, so that the same random numbers make the same
+		// swaps.
+		edges := slices.SortedFunc(maps.Keys(res), func(x, y edge) int {
+			return cmp.Or(cmp.Compare(x.P1, y.P1), cmp.Compare(x.P2, y.P2))
+		})
+
+		bad := slices.IndexFunc(edges, func(e edge) bool { return forbidden(int(e.P1), int(e.P2)) })
+		if bad >= 0 {
+			e, f := edges[bad], edges[random.IntN(len(edges))]
+			if _, ok := isRequired[f]; ok || f == e {
+				continue
+			}
+			a, b, c, d := int(e.P1), int(e.P2), int(f.P1), int(f.P2)
+			if random.IntN(2) == 0 {
+				c, d = d, c
+			}
+			swap([2]edge{e, f}, [2][2]int{{a, c}, {b, d}})
+			continue
+		}
+
+		missing := slices.IndexFunc(required, func(e edge) bool { return !has(int(e.P1), int(e.P2)) })
+		if missing < 0 {
+			return res, nil
+		}
+		a, b := int(required[missing].P1), int(required[missing].P2)
+		var fromA, fromB []edge
+		for _, e := range edges {
+			if _, ok := isRequired[e]; ok {
+				continue
+			}
+			if int(e.P1) == a || int(e.P2) == a {
+				fromA = append(fromA, e)
+			}
+			if int(e.P1) == b || int(e.P2) == b {
+				fromB = append(fromB, e)
+			}
+		}
+		if len(fromA) == 0 || len(fromB) == 0 {
+			return nil, errors.New("the teams that must face each other have no matches to give up")
+		}
+		ea, eb := fromA[random.IntN(len(fromA))], fromB[random.IntN(len(fromB))]
+		c, d := otherEnd(ea, a), otherEnd(eb, b)
+		swap([2]edge{ea, eb}, [2][2]int{{a, b}, {c, d}})
+	}
+
+	return nil, errors.New("could not make the matches follow the constraints")
+}
+
+func otherEnd(e edge, n int) int {
+	if int(e.P1) == n {
+		return int(e.P2)
+	}
+	return int(e.P1)
+}
+
+// Makes sure that the people that must face each other do: for every such
+// pair of people, a team of one and a team of the other can only face each
+// other. Graph holds the matches the teams can play, and every team plays
+// exactly one of them.
+func (c Constraints) forceMustFace(graph *Graph, teams []Team) error {
+	forced := make(nodeSet)
+	for _, p := range c.MustFace {
+		var match *edge
+		for e := range graph.GetEdgesIterator() {
+			a, b := int(e.P1), int(e.P2)
+			if !forced.contains(a) && !forced.contains(b) &&
+				teamsContainPair([]PersonPair{p}, teams[a], teams[b]) {
+				match = &e
+				break
+			}
+		}
+		if match == nil {
+			return fmt.Errorf("%s and %s cannot face each other", p[0].Id, p[1].Id)
+		}
+
+		for _, ends := range [][2]Node{{match.P1, match.P2}, {match.P2, match.P1}} {
+			for _, other := range graph.GetAdjacentEdges(ends[0]) {
+				if other.P2 != ends[1] {
+					graph.RemoveEdge(other)
+				}
+			}
+		}
+		forced[int(match.P1)], forced[int(match.P2)] = struct{}{}, struct{}{}
+	}
+	return nil
+}
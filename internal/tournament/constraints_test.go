@@ -0,0 +1,119 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestConstraints(t *testing.T) {
+	t.Run("Assertion_1_RodeoFollowsConstraints", func(t *testing.T) {
+		teams := makeTeams(8)
+		// By default Team00 faces Team01, but never Team02.
+		constraints := Constraints{
+			NeverFace: []PersonPair{{teams[0].Person1, teams[1].Person2}},
+			MustFace:  []PersonPair{{teams[0].Person2, teams[2].Person1}},
+		}
+		rodeoFactory := RodeoFactory{MaxRounds: 3, AvailableCourts: 4, Constraints: constraints}
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+
+		faced := make(map[Team]int)
+		for _, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				if *m.TeamA == teams[0] {
+					faced[*m.TeamB]++
+				}
+				if *m.TeamB == teams[0] {
+					faced[*m.TeamA]++
+				}
+			}
+		}
+		if faced[teams[1]] != 0 {
+			t.Errorf("Team00 faces Team01")
+		}
+		if faced[teams[2]] != 1 {
+			t.Errorf("expected Team00 to face Team02 once, got %d", faced[teams[2]])
+		}
+		if len(faced) != 3 {
+			t.Errorf("expected Team00 to play 3 matches, got %v", faced)
+		}
+	})
+
+	t.Run("Assertion_2_TeamOfPeopleThatMustNeverBePartners", func(t *testing.T) {
+		teams := makeTeams(8)
+		rodeoFactory := RodeoFactory{
+			MaxRounds:       3,
+			AvailableCourts: 4,
+			Constraints:     Constraints{NeverPartners: []PersonPair{{teams[3].Person1, teams[3].Person2}}},
+		}
+		if _, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, time.Now()); err == nil {
+			t.Errorf("expected an error for a team of people that must never be partners")
+		}
+	})
+
+	t.Run("Assertion_3_SinglePlayerRodeoFollowsConstraints", func(t *testing.T) {
+		people := make(map[string]Person)
+		for i := range 8 {
+			people[fmt.Sprintf("P%d", i)] = Person{Id: fmt.Sprintf("P%d", i)}
+		}
+		constraints := Constraints{
+			NeverPartners: []PersonPair{{{Id: "P0"}, {Id: "P1"}}},
+			NeverFace:     []PersonPair{{{Id: "P2"}, {Id: "P3"}}},
+			MustFace:      []PersonPair{{{Id: "P4"}, {Id: "P5"}}},
+		}
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          people,
+			Constraints:     constraints,
+		}
+		rodeo := makeFirstValidRodeo(t, factory)
+		if err := constraints.validateRounds(rodeo.Rounds); err != nil {
+			t.Errorf("the rounds do not follow the constraints: %v", err)
+		}
+	})
+
+	t.Run("Assertion_4_RewiringKeepsMatchesPerNode", func(t *testing.T) {
+		nodes := []int{0, 1, 2, 3, 4, 5, 6, 7}
+		m, err := rewireMatching(
+			makeMatching(nodes, 3),
+			func(a, b int) bool { return a == 0 && b == 1 },
+			[]edge{{P1: 0, P2: 2}},
+			rand.New(rand.NewPCG(1, 2)),
+		)
+		if err != nil {
+			t.Fatalf("rewireMatching returned an error: %v", err)
+		}
+		degree := make(map[Node]int)
+		for e := range m {
+			degree[e.P1]++
+			degree[e.P2]++
+		}
+		for _, n := range nodes {
+			if degree[Node(n)] != 3 {
+				t.Errorf("expected node %d to have 3 edges, got %d", n, degree[Node(n)])
+			}
+		}
+		if _, ok := m[edge{P1: 0, P2: 1}]; ok {
+			t.Errorf("forbidden edge still in the matching")
+		}
+		if _, ok := m[edge{P1: 0, P2: 2}]; !ok {
+			t.Errorf("required edge not in the matching")
+		}
+	})
+
+	t.Run("Assertion_5_ContradictoryConstraints", func(t *testing.T) {
+		constraints := Constraints{
+			NeverFace: []PersonPair{{{Id: "A"}, {Id: "B"}}},
+			MustFace:  []PersonPair{{{Id: "B"}, {Id: "A"}}},
+		}
+		if err := constraints.validate(nil); err == nil {
+			t.Errorf("expected an error for people that must both face and never face each other")
+		}
+	})
+}
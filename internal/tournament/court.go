@@ -0,0 +1,83 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type TimeWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+func (w TimeWindow) overlaps(start, end time.Time) bool {
+	return start.Before(w.End) && w.Start.Before(end)
+}
+
+// Court is a court the organisers can use, identified by its real number.
+// The court is free from From until Until, except during its blackouts, e.g.
+// while it hosts a lesson. A zero From or Until means that the court is free
+// from the start or until the end of the tournament.
+type Court struct {
+	Id        int          `json:"id"`
+	From      time.Time    `json:"from"`
+	Until     time.Time    `json:"until"`
+	Blackouts []TimeWindow `json:"blackouts"`
+}
+
+// IsAvailable tells whether the court is free for the whole period from
+// start to end.
+func (c Court) IsAvailable(start, end time.Time) bool {
+	if !c.From.IsZero() && start.Before(c.From) {
+		return false
+	}
+	if !c.Until.IsZero() && end.After(c.Until) {
+		return false
+	}
+	for _, b := range c.Blackouts {
+		if b.overlaps(start, end) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateCourts(courts []Court) error {
+	ids := make(map[int]any)
+	for _, c := range courts {
+		if _, ok := ids[c.Id]; ok {
+			return fmt.Errorf("court %d is listed more than once", c.Id)
+		}
+		ids[c.Id] = struct{}{}
+	}
+	return nil
+}
+
+// Returns, for every round, the ids of the courts that are free for the whole
+// round. Round i starts after i matches and changeovers from dateStart.
+func getCourtsPerRound(
+	courts []Court,
+	roundsNumber int,
+	dateStart time.Time,
+	matchDuration, changeover time.Duration,
+) ([][]int, error) {
+	if matchDuration <= 0 {
+		return nil, errors.New("a match duration is needed to check when the courts are available")
+	}
+	if err := validateCourts(courts); err != nil {
+		return nil, err
+	}
+
+	res := make([][]int, roundsNumber)
+	for i := range roundsNumber {
+		start := dateStart.Add(time.Duration(i) * (matchDuration + changeover))
+		end := start.Add(matchDuration)
+		for _, c := range courts {
+			if c.IsAvailable(start, end) {
+				res[i] = append(res[i], c.Id)
+			}
+		}
+	}
+	return res, nil
+}
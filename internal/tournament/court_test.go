@@ -0,0 +1,70 @@
+package tournament
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMakeTournamentOnCourts(t *testing.T) {
+	teams := makeTeams(6)
+	dateStart := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	// Rounds start at 18:00, 18:25, 18:50, 19:15 and 19:40.
+	courts := []Court{
+		{Id: 1},
+		{Id: 4, Blackouts: []TimeWindow{{Start: at(18, 20), End: at(19, 0)}}},
+		{Id: 7, From: at(19, 0)},
+	}
+	freeCourts := [][]int{{1, 4}, {1}, {1}, {1, 4, 7}, {1, 4, 7}}
+
+	rodeoFactory := RodeoFactory{
+		MaxRounds:     5,
+		MatchDuration: 20 * time.Minute,
+		Changeover:    5 * time.Minute,
+		Courts:        courts,
+	}
+	rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+
+	t.Run("Assertion_1_MatchesOnFreeCourts", func(t *testing.T) {
+		for i, round := range rodeo.GetRounds() {
+			if len(round.Matches) > len(freeCourts[i]) {
+				t.Errorf("Round %d: %d matches on %d free courts", i+1, len(round.Matches), len(freeCourts[i]))
+			}
+			for _, m := range round.Matches {
+				if !slices.Contains(freeCourts[i], m.CourtId) {
+					t.Errorf("Round %d: match on court %d, free courts are %v", i+1, m.CourtId, freeCourts[i])
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_EveryTeamPlaysTheSame", func(t *testing.T) {
+		played := make(map[Team]int)
+		for _, round := range rodeo.GetRounds() {
+			for _, m := range round.Matches {
+				played[*m.TeamA]++
+				played[*m.TeamB]++
+			}
+		}
+		for _, team := range teams {
+			if played[team] != 3 {
+				t.Errorf("expected %v to play 3 matches, got %d", team, played[team])
+			}
+		}
+	})
+
+	t.Run("Assertion_3_CourtsNeedMatchDuration", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{MaxRounds: 5, Courts: courts}
+		if _, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart); err == nil {
+			t.Errorf("expected an error when courts have windows but matches have no duration")
+		}
+	})
+}
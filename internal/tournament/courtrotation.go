@@ -0,0 +1,142 @@
+package tournament
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+)
+
+// Rounds with more matches than this are balanced greedily, one match at a
+// time, instead of trying every assignment of matches to courts.
+const maxExactCourtBalance = 16
+
+// CourtWeights tells how much playing on a court counts when courts are
+// rotated, e.g. a show court with weight 3 is shared out three times as
+// carefully as the other courts. Courts that are not listed weigh 1.
+type CourtWeights map[int]float64
+
+func (cw CourtWeights) weight(courtId int) float64 {
+	if w, ok := cw[courtId]; ok {
+		return w
+	}
+	return 1
+}
+
+// How many times every person, by their Id, played on every court.
+type courtUses map[string]map[int]int
+
+func (cu courtUses) add(round Round) {
+	for _, m := range round.Matches {
+		for _, p := range matchPeople(m) {
+			if cu[p.Id] == nil {
+				cu[p.Id] = make(map[int]int)
+			}
+			cu[p.Id][m.CourtId]++
+		}
+	}
+}
+
+func matchPeople(m Match) []Person {
+	var res []Person
+	for _, team := range []*Team{m.TeamA, m.TeamB} {
+		if team == nil {
+			continue
+		}
+		for _, p := range []Person{team.Person1, team.Person2} {
+			if !p.IsNil() {
+				res = append(res, p)
+			}
+		}
+	}
+	return res
+}
+
+// Rotates the courts of the rounds from the round from on, so that everyone
+// plays on every court as evenly as possible. The rounds before from are only
+// used to know where everyone already played. Every round keeps the courts it
+// uses, only the matches played on them change. King of the court, mexicano
+// and interclub rounds are not rotated, as their court numbers tell the level
+// of the match or the rubber.
+func balanceCourts(rounds []Round, from int, weights CourtWeights) {
+	uses := make(courtUses)
+	for _, round := range rounds[:from] {
+		uses.add(round)
+	}
+	for _, round := range rounds[from:] {
+		balanceRoundCourts(uses, round, weights)
+		uses.add(round)
+	}
+}
+
+// Like balanceCourts, for a round that will follow history.
+func balanceNextRoundCourts(history []Round, round Round, weights CourtWeights) {
+	uses := make(courtUses)
+	for _, r := range history {
+		uses.add(r)
+	}
+	balanceRoundCourts(uses, round, weights)
+}
+
+// Assigns the courts of the round to its matches so that the people of every
+// match played as little as possible on their court, counting every court
+// with its weight.
+func balanceRoundCourts(uses courtUses, round Round, weights CourtWeights) {
+	n := len(round.Matches)
+	if n < 2 {
+		return
+	}
+
+	original := make([]int, n)
+	for i, m := range round.Matches {
+		original[i] = m.CourtId
+	}
+	courts := slices.Sorted(slices.Values(original))
+
+	cost := func(i, court int) float64 {
+		total := 0
+		for _, p := range matchPeople(round.Matches[i]) {
+			total += uses[p.Id][court]
+		}
+		return weights.weight(court) * float64(total)
+	}
+
+	if n > maxExactCourtBalance {
+		free := slices.Clone(courts)
+		for i := range round.Matches {
+			best := 0
+			for j := range free {
+				if cost(i, free[j]) < cost(i, free[best]) ||
+					(cost(i, free[j]) == cost(i, free[best]) && free[j] == original[i]) {
+					best = j
+				}
+			}
+			round.Matches[i].CourtId = free[best]
+			free = slices.Delete(free, best, best+1)
+		}
+		return
+	}
+
+	// best[mask] is the lowest cost of playing the first matches, as many as
+	// the courts in mask, on the courts in mask.
+	best := make([]float64, 1<<n)
+	choice := make([]int, 1<<n)
+	for mask := 1; mask < len(best); mask++ {
+		best[mask] = math.Inf(1)
+		i := bits.OnesCount(uint(mask)) - 1
+		for c := range n {
+			if mask&(1<<c) == 0 {
+				continue
+			}
+			// Between equal assignments, matches stay on their court.
+			total := best[mask&^(1<<c)] + cost(i, courts[c])
+			if total < best[mask] || (total == best[mask] && courts[c] == original[i]) {
+				best[mask], choice[mask] = total, c
+			}
+		}
+	}
+
+	for mask := len(best) - 1; mask > 0; mask &^= 1 << choice[mask] {
+		i := bits.OnesCount(uint(mask)) - 1
+		round.Matches[i].CourtId = courts[choice[mask]]
+	}
+}
@@ -0,0 +1,100 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+// Returns, for every team, how many matches it played on every court.
+func countCourts(rounds []Round) map[Team]map[int]int {
+	res := make(map[Team]map[int]int)
+	for _, round := range rounds {
+		for _, m := range round.Matches {
+			for _, team := range []Team{*m.TeamA, *m.TeamB} {
+				if res[team] == nil {
+					res[team] = make(map[int]int)
+				}
+				res[team][m.CourtId]++
+			}
+		}
+	}
+	return res
+}
+
+func TestBalanceCourts(t *testing.T) {
+	teams := makeTeams(8)
+
+	t.Run("Assertion_1_TeamsRotateOverCourts", func(t *testing.T) {
+		// Without rotation the circle method keeps the first team on court 1.
+		roundRobinFactory := RoundRobinFactory{AvailableCourts: 4, Legs: 1, DaysBetweenRounds: 1}
+		roundRobin, err := roundRobinFactory.MakeTournament("round robin", teams, time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		for team, courts := range countCourts(roundRobin.Rounds) {
+			for court, count := range courts {
+				if count > 3 {
+					t.Errorf("%v played %d of 7 matches on court %d", team, count, court)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_RoundsKeepTheirCourts", func(t *testing.T) {
+		rounds := []Round{
+			{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[1], CourtId: 2},
+				{TeamA: &teams[2], TeamB: &teams[3], CourtId: 5},
+			}},
+			{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[2], CourtId: 2},
+				{TeamA: &teams[1], TeamB: &teams[3], CourtId: 5},
+			}},
+		}
+		balanceCourts(rounds, 0, nil)
+		if rounds[0].Matches[0].CourtId != 2 || rounds[0].Matches[1].CourtId != 5 {
+			t.Errorf("expected the first round to keep its courts, got %v", rounds[0].Matches)
+		}
+		// Every team of the second round played once on each court.
+		if rounds[1].Matches[0].CourtId != 2 && rounds[1].Matches[0].CourtId != 5 {
+			t.Errorf("expected the second round on courts 2 and 5, got %v", rounds[1].Matches)
+		}
+	})
+
+	t.Run("Assertion_3_ShowCourtWeight", func(t *testing.T) {
+		// Team 0 played once on court 1 and twice on court 2.
+		history := []Round{
+			{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[4], CourtId: 1},
+				{TeamA: &teams[5], TeamB: &teams[6], CourtId: 2},
+			}},
+			{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[5], CourtId: 2},
+				{TeamA: &teams[4], TeamB: &teams[6], CourtId: 1},
+			}},
+			{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[6], CourtId: 2},
+				{TeamA: &teams[4], TeamB: &teams[5], CourtId: 1},
+			}},
+		}
+		next := func() Round {
+			return Round{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[1], CourtId: 1},
+				{TeamA: &teams[2], TeamB: &teams[3], CourtId: 2},
+			}}
+		}
+
+		round := next()
+		balanceNextRoundCourts(history, round, nil)
+		if round.Matches[0].CourtId != 1 {
+			t.Errorf("expected team 0 on court 1, got court %d", round.Matches[0].CourtId)
+		}
+
+		// When court 1 is the show court, team 0 leaves it to the others.
+		round = next()
+		balanceNextRoundCourts(history, round, CourtWeights{1: 3})
+		if round.Matches[0].CourtId != 2 {
+			t.Errorf("expected team 0 away from the show court, got court %d", round.Matches[0].CourtId)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package tournament
+
+import (
+	"cmp"
+	"errors"
+	"math/rand/v2"
+	"slices"
+)
+
+// Colours the edges of a graph, so that the edges of a node all have
+// different colours. Every colour is a matching, i.e. a round where no team
+// plays twice.
+type edgeColouring struct {
+	// at[n][c] is the node n faces with an edge of colour c, or -1.
+	at [][]int
+}
+
+func makeEdgeColouring(nodes, colours int) edgeColouring {
+	at := make([][]int, nodes)
+	for n := range at {
+		at[n] = slices.Repeat([]int{-1}, colours)
+	}
+	return edgeColouring{at: at}
+}
+
+func (ec edgeColouring) set(u, v, c int) {
+	ec.at[u][c], ec.at[v][c] = v, u
+}
+
+func (ec edgeColouring) unset(u, v, c int) {
+	ec.at[u][c], ec.at[v][c] = -1, -1
+}
+
+func (ec edgeColouring) isFree(u, c int) bool {
+	return ec.at[u][c] == -1
+}
+
+// Returns the smallest of the first colours that no edge of u has, or -1.
+func (ec edgeColouring) free(u, colours int) int {
+	return slices.Index(ec.at[u][:colours], -1)
+}
+
+func (ec edgeColouring) colourOf(u, v int) int {
+	return slices.Index(ec.at[u], v)
+}
+
+// Returns the nodes of the path that starts from u with an edge of colour a,
+// and then alternates edges of colours b and a.
+func (ec edgeColouring) chain(u, a, b int) []int {
+	path := []int{u}
+	for c := a; !ec.isFree(path[len(path)-1], c); c = otherColour(c, a, b) {
+		next := ec.at[path[len(path)-1]][c]
+		if next == path[0] {
+			break
+		}
+		path = append(path, next)
+	}
+	return path
+}
+
+func otherColour(c, a, b int) int {
+	if c == a {
+		return b
+	}
+	return a
+}
+
+// Swaps the colours a and b of the edges of a chain starting with colour a.
+func (ec edgeColouring) swapChain(path []int, a, b int) {
+	for i, c := 0, a; i+1 < len(path); i, c = i+1, otherColour(c, a, b) {
+		ec.unset(path[i], path[i+1], c)
+	}
+	for i, c := 0, b; i+1 < len(path); i, c = i+1, otherColour(c, a, b) {
+		ec.set(path[i], path[i+1], c)
+	}
+}
+
+func (ec edgeColouring) size(c int) int {
+	res := 0
+	for u := range ec.at {
+		if ec.at[u][c] > u {
+			res++
+		}
+	}
+	return res
+}
+
+func (ec edgeColouring) matchings(colours int) matchings {
+	res := make(matchings, colours)
+	for c := range res {
+		res[c] = make(matching)
+		for u := range ec.at {
+			if v := ec.at[u][c]; v > u {
+				res[c].addCanonicalEdge(u, v)
+			}
+		}
+	}
+	return res
+}
+
+// Colours the edges with at most maxDegree+1 colours with the Misra–Gries
+// algorithm: every edge is coloured in turn, after rotating a fan of edges
+// around one of its nodes and inverting a path of two alternating colours.
+func misraGries(ec edgeColouring, edges []edge, maxDegree int) error {
+	colours := maxDegree + 1
+	for _, e := range edges {
+		x, f0 := int(e.P1), int(e.P2)
+
+		// The fan of x: the colour of every edge is free on the node of the
+		// previous edge.
+		fan := []int{f0}
+		for {
+			last := fan[len(fan)-1]
+			next := -1
+			for c := range colours {
+				v := ec.at[x][c]
+				if v != -1 && !slices.Contains(fan, v) && ec.isFree(last, c) {
+					next = v
+					break
+				}
+			}
+			if next == -1 {
+				break
+			}
+			fan = append(fan, next)
+		}
+
+		c, d := ec.free(x, colours), ec.free(fan[len(fan)-1], colours)
+		if c == -1 || d == -1 {
+			return errors.New("a node has more edges than the maximum degree")
+		}
+		if c != d && !ec.isFree(x, d) {
+			ec.swapChain(ec.chain(x, d, c), d, c)
+		}
+
+		w := -1
+		for j, f := range fan {
+			if j > 0 && !ec.isFree(fan[j-1], ec.colourOf(x, f)) {
+				break
+			}
+			if ec.isFree(f, d) {
+				w = j
+				break
+			}
+		}
+		if w == -1 {
+			return errors.New("could not find the end of the fan to rotate")
+		}
+
+		for i := range w {
+			c := ec.colourOf(x, fan[i+1])
+			ec.unset(x, fan[i+1], c)
+			ec.set(x, fan[i], c)
+		}
+		ec.set(x, fan[w], d)
+	}
+	return nil
+}
+
+// Moves edges between colours until colour c has at most maxSizes[c] edges.
+// An alternating path of colours a and b that starts and ends with a moves
+// one edge from a to b when its colours are swapped. When there is no such
+// path, the two colours free on the ends of an edge of a are made the same
+// by swapping a path of those colours (a Kempe chain). When that fails too,
+// e.g. when every team plays in every round, a random Kempe chain is swapped
+// and the search goes on.
+func equaliseColours(ec edgeColouring, maxSizes []int) error {
+	colours := len(maxSizes)
+	maxSteps := len(ec.at) * len(ec.at) * colours
+	random := rand.New(rand.NewPCG(uint64(len(ec.at)), uint64(colours)))
+
+	// Every step moves an edge from a colour with too many edges, without
+	// giving too many to another one.
+	for range maxSteps {
+		sizes := make([]int, colours)
+		for c := range sizes {
+			sizes[c] = ec.size(c)
+		}
+		a := -1
+		var spare []int
+		for c := range colours {
+			if sizes[c] > maxSizes[c] && a == -1 {
+				a = c
+			}
+			if sizes[c] < maxSizes[c] {
+				spare = append(spare, c)
+			}
+		}
+		if a == -1 {
+			return nil
+		}
+		// Emptier colours first, so that the rounds end up of similar sizes.
+		slices.SortStableFunc(spare, func(x, y int) int { return cmp.Compare(sizes[x], sizes[y]) })
+
+		if !moveAlongPath(ec, a, spare) && !moveWithKempeChain(ec, a, spare) {
+			swapRandomChain(ec, a, maxSizes, random)
+		}
+	}
+	return errors.New("could not spread the matches over the rounds")
+}
+
+// Swaps a path that starts and ends with colour a, and alternates it with one
+// of the spare colours.
+func moveAlongPath(ec edgeColouring, a int, spare []int) bool {
+	for _, b := range spare {
+		for u := range ec.at {
+			if ec.isFree(u, a) || !ec.isFree(u, b) {
+				continue
+			}
+			path := ec.chain(u, a, b)
+			if len(path)%2 == 0 {
+				ec.swapChain(path, a, b)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Moves an edge (u, v) of colour a to a spare colour s free on u, after
+// swapping the path of colours s and t starting from v, where t is a spare
+// colour free on v.
+func moveWithKempeChain(ec edgeColouring, a int, spare []int) bool {
+	for u := range ec.at {
+		v := ec.at[u][a]
+		if v == -1 {
+			continue
+		}
+		for _, s := range spare {
+			if !ec.isFree(u, s) {
+				continue
+			}
+			for _, t := range spare {
+				if t == s || !ec.isFree(v, t) {
+					continue
+				}
+				path := ec.chain(v, s, t)
+				if path[len(path)-1] == u {
+					continue
+				}
+				ec.swapChain(path, s, t)
+				ec.unset(u, v, a)
+				ec.set(u, v, s)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Swaps the chain starting from the end of a random edge of colour a, with
+// one of its free colours and another colour of the rounds, so that other
+// colours become free on it.
+func swapRandomChain(ec edgeColouring, a int, maxSizes []int, random *rand.Rand) {
+	var ends []int
+	for u := range ec.at {
+		if !ec.isFree(u, a) {
+			ends = append(ends, u)
+		}
+	}
+	u := ends[random.IntN(len(ends))]
+
+	var free, used []int
+	for c, size := range maxSizes {
+		if c == a || size == 0 {
+			continue
+		}
+		if ec.isFree(u, c) {
+			free = append(free, c)
+		} else {
+			used = append(used, c)
+		}
+	}
+	if len(free) == 0 || len(used) == 0 {
+		return
+	}
+	s, t := free[random.IntN(len(free))], used[random.IntN(len(used))]
+	ec.swapChain(ec.chain(u, t, s), t, s)
+}
+
+// Splits the edges of the graph between nodes 0 to nodes-1 into
+// len(maxSizes) matchings, the matching i with at most maxSizes[i] edges, in
+// polynomial time.
+func makeMatchingsColouring(graph Graph, nodes int, maxSizes []int) (matchings, error) {
+	var edges []edge
+	maxDegree := 0
+	for e := range graph.GetEdgesIterator() {
+		edges = append(edges, edge{P1: min(e.P1, e.P2), P2: max(e.P1, e.P2)})
+	}
+	for n := range graph.nodes {
+		maxDegree = max(maxDegree, len(graph.nodes[n]))
+	}
+	// The same graph is always coloured in the same way.
+	slices.SortFunc(edges, func(x, y edge) int {
+		return cmp.Or(cmp.Compare(x.P1, y.P1), cmp.Compare(x.P2, y.P2))
+	})
+
+	colours := max(maxDegree+1, len(maxSizes))
+	ec := makeEdgeColouring(nodes, colours)
+	if err := misraGries(ec, edges, maxDegree); err != nil {
+		return nil, err
+	}
+
+	// Colours that are not rounds must end up without edges.
+	sizes := make([]int, colours)
+	copy(sizes, maxSizes)
+	if err := equaliseColours(ec, sizes); err != nil {
+		return nil, err
+	}
+	return ec.matchings(len(maxSizes)), nil
+}
@@ -0,0 +1,93 @@
+package tournament
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMakeMatchingsColouring(t *testing.T) {
+	t.Run("Assertion_1_EveryConfigurationIsSplitIntoRounds", func(t *testing.T) {
+		for n := 2; n <= 20; n++ {
+			for rounds := 1; rounds <= 10; rounds++ {
+				for courts := 1; courts <= n; courts++ {
+					totalMatches, matchesPerTurn, matchesPerTeam := getMatchesPerTeam(n, rounds, courts)
+					if totalMatches == 0 {
+						continue
+					}
+					roundsNumber := getRoundsNumber(rounds, totalMatches, matchesPerTurn)
+					sizes := uniformRoundSizes(matchesPerTurn, roundsNumber)
+
+					rf := RodeoFactory{MaxRounds: rounds, AvailableCourts: courts}
+					graph, teams, err := rf.getGraph(makeTeams(n), matchesPerTeam)
+					if err != nil {
+						t.Fatalf("%d teams, %d rounds, %d courts: %v", n, rounds, courts, err)
+					}
+					res, err := makeMatchingsColouring(graph, len(teams), sizes)
+					if err != nil {
+						t.Fatalf("%d teams, %d rounds, %d courts: %v", n, rounds, courts, err)
+					}
+					matches := uniformMatches(n, matchesPerTeam)
+					err = validateTournamentRounds(res, teams, roundsNumber, sizes, matches, matches, nil)
+					if err != nil {
+						t.Errorf("%d teams, %d rounds, %d courts: %v", n, rounds, courts, err)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_RoundsOfDifferentSizes", func(t *testing.T) {
+		sizes := []int{4, 0, 2, 4, 3, 4, 3}
+		totalMatches, matchesPerTeam := getMatchesPerTeamByRound(8, sizes)
+		sizes = balanceRoundSizes(sizes, totalMatches)
+
+		rf := RodeoFactory{}
+		graph, teams, err := rf.getGraph(makeTeams(8), matchesPerTeam)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := makeMatchingsColouring(graph, len(teams), sizes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches := uniformMatches(8, matchesPerTeam)
+		if err := validateTournamentRounds(res, teams, len(sizes), sizes, matches, matches, nil); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestMakeTournamentEveryTeamPlaysEveryRound(t *testing.T) {
+	rodeoFactory := RodeoFactory{MaxRounds: 17, AvailableCourts: 9}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	rodeo, err := rodeoFactory.MakeTournament(ctx, "rodeo", makeTeams(18), time.Now())
+	if err != nil {
+		t.Fatalf("expected 18 teams to play a round robin in 17 rounds: %v", err)
+	}
+
+	t.Run("Assertion_1_EveryRoundIsFull", func(t *testing.T) {
+		if len(rodeo.Rounds) != 17 {
+			t.Fatalf("expected 17 rounds, got %d", len(rodeo.Rounds))
+		}
+		for i, round := range rodeo.Rounds {
+			if len(round.Matches) != 9 {
+				t.Errorf("expected 9 matches in round %d, got %d", i+1, len(round.Matches))
+			}
+		}
+	})
+
+	t.Run("Assertion_2_EveryPairFacesOnce", func(t *testing.T) {
+		faced := make(map[[2]string]int)
+		for _, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				faced[personPair(m.TeamA.Person1, m.TeamB.Person1)]++
+			}
+		}
+		if len(faced) != 18*17/2 {
+			t.Errorf("expected %d pairs of teams to face each other, got %d", 18*17/2, len(faced))
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package tournament
+
+import "math"
+
+// ScheduleOption is a combination of rounds and courts a rodeo can be
+// generated with, and the schedule that results from it.
+type ScheduleOption struct {
+	Rounds int `json:"rounds"`
+	Courts int `json:"courts"`
+	// PlayedRounds can be less than Rounds, when fewer rounds already hold
+	// all the matches.
+	PlayedRounds          int `json:"playedRounds"`
+	MatchesPerParticipant int `json:"matchesPerParticipant"`
+	MatchesPerRound       int `json:"matchesPerRound"`
+	TotalMatches          int `json:"totalMatches"`
+	// RestsPerParticipant is the number of played rounds every participant
+	// sits out.
+	RestsPerParticipant int `json:"restsPerParticipant"`
+	// RestingPerRound is the number of participants that sit out a full
+	// round.
+	RestingPerRound int `json:"restingPerRound"`
+}
+
+// ExploreRodeo lists the feasible combinations of at most maxRounds rounds
+// and maxCourts courts for a rodeo of the given number of teams. Courts that
+// can never all be used, because there are not enough teams, are left out.
+func ExploreRodeo(teams, maxRounds, maxCourts int) []ScheduleOption {
+	var res []ScheduleOption
+	for rounds := 1; rounds <= maxRounds; rounds++ {
+		for courts := 1; courts <= min(maxCourts, teams/2); courts++ {
+			totalMatches, matchesPerTurn, matchesPerTeam := getMatchesPerTeam(teams, rounds, courts)
+			if totalMatches == 0 {
+				continue
+			}
+			matchesPerRound := int(math.Ceil(matchesPerTurn))
+			// Rounds left without matches are not played.
+			playedRounds := min(getRoundsNumber(rounds, totalMatches, matchesPerTurn), totalMatches)
+			res = append(res, ScheduleOption{
+				Rounds:                rounds,
+				Courts:                courts,
+				PlayedRounds:          playedRounds,
+				MatchesPerParticipant: matchesPerTeam,
+				MatchesPerRound:       matchesPerRound,
+				TotalMatches:          totalMatches,
+				RestsPerParticipant:   playedRounds - matchesPerTeam,
+				RestingPerRound:       teams - 2*matchesPerRound,
+			})
+		}
+	}
+	return res
+}
+
+// ExploreSinglePlayerRodeo lists the feasible combinations of at most
+// maxRounds rounds and maxCourts courts for a single player rodeo of the
+// given number of people. Courts that can never all be used, because there
+// are not enough people, are left out.
+func ExploreSinglePlayerRodeo(people, maxRounds, maxCourts int) []ScheduleOption {
+	var res []ScheduleOption
+	for rounds := 1; rounds <= maxRounds; rounds++ {
+		for courts := 1; courts <= min(maxCourts, people/4); courts++ {
+			matches := getMatchesPerPerson(people, rounds, courts)
+			if matches.MatchesPerPerson == 0 {
+				continue
+			}
+			res = append(res, ScheduleOption{
+				Rounds:                rounds,
+				Courts:                courts,
+				PlayedRounds:          rounds,
+				MatchesPerParticipant: matches.MatchesPerPerson,
+				MatchesPerRound:       matches.MatchesPerRound,
+				TotalMatches:          matches.TotalMatches,
+				RestsPerParticipant:   rounds - matches.MatchesPerPerson,
+				RestingPerRound:       people - 4*matches.MatchesPerRound,
+			})
+		}
+	}
+	return res
+}
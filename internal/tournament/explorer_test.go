@@ -0,0 +1,89 @@
+package tournament
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExploreRodeo(t *testing.T) {
+	t.Run("Assertion_1_InfeasibleCombinationsAreLeftOut", func(t *testing.T) {
+		options := ExploreRodeo(5, 3, 4)
+		for _, o := range options {
+			if o.Rounds < 3 {
+				t.Errorf("5 teams cannot all play in %d rounds, got %+v", o.Rounds, o)
+			}
+			if o.Courts > 2 {
+				t.Errorf("5 teams cannot use %d courts, got %+v", o.Courts, o)
+			}
+		}
+		expected := ScheduleOption{
+			Rounds:                3,
+			Courts:                2,
+			PlayedRounds:          3,
+			MatchesPerParticipant: 2,
+			MatchesPerRound:       2,
+			TotalMatches:          5,
+			RestsPerParticipant:   1,
+			RestingPerRound:       1,
+		}
+		if !slices.Contains(options, expected) {
+			t.Errorf("expected %+v, got %+v", expected, options)
+		}
+	})
+
+	t.Run("Assertion_2_EveryTeamPlaysEveryRound", func(t *testing.T) {
+		options := ExploreRodeo(8, 1, 4)
+		expected := []ScheduleOption{{
+			Rounds:                1,
+			Courts:                4,
+			PlayedRounds:          1,
+			MatchesPerParticipant: 1,
+			MatchesPerRound:       4,
+			TotalMatches:          4,
+		}}
+		if !slices.Equal(options, expected) {
+			t.Errorf("expected %+v, got %+v", expected, options)
+		}
+	})
+
+	t.Run("Assertion_3_RoundsWithoutMatchesAreNotPlayed", func(t *testing.T) {
+		// 4 teams play 6 matches, one per round.
+		options := ExploreRodeo(4, 8, 1)
+		expected := []ScheduleOption{{
+			Rounds:                8,
+			Courts:                1,
+			PlayedRounds:          6,
+			MatchesPerParticipant: 3,
+			MatchesPerRound:       1,
+			TotalMatches:          6,
+			RestsPerParticipant:   3,
+			RestingPerRound:       2,
+		}}
+		if !slices.Equal(options[len(options)-1:], expected) {
+			t.Errorf("expected %+v, got %+v", expected, options[len(options)-1:])
+		}
+	})
+}
+
+func TestExploreSinglePlayerRodeo(t *testing.T) {
+	t.Run("Assertion_1_MatchesAddUp", func(t *testing.T) {
+		options := ExploreSinglePlayerRodeo(10, 5, 3)
+		if len(options) == 0 {
+			t.Fatalf("expected some options for 10 people")
+		}
+		for _, o := range options {
+			if o.Courts > 2 {
+				t.Errorf("10 people cannot use %d courts, got %+v", o.Courts, o)
+			}
+			if o.TotalMatches*4 != o.MatchesPerParticipant*10 {
+				t.Errorf("matches of %+v do not add up", o)
+			}
+			if o.MatchesPerRound > o.Courts {
+				t.Errorf("matches of a round do not fit on the courts of %+v", o)
+			}
+			if o.RestsPerParticipant+o.MatchesPerParticipant != o.Rounds {
+				t.Errorf("rests and matches of %+v do not add up to the rounds", o)
+			}
+		}
+	})
+}
@@ -211,3 +211,67 @@ func (g *Graph) AddEdge(e edge) {
 	}
 	g.nodes[e.P2][e.P1] = true
 }
+
+func (g Graph) HasEdge(e edge) bool {
+	return g.nodes[e.P1][e.P2]
+}
+
+// Builds the rounds of a round robin between n nodes with the circle method:
+// node 0 stays fixed while the others rotate around it, so that every pair of
+// nodes appears in exactly one of the returned rounds. When n is odd a phantom
+// node is added, whoever is paired with it rests for that round.
+func circleMethod(n int) [][]edge {
+	if n < 2 {
+		return nil
+	}
+
+	size := n
+	if size%2 != 0 {
+		size += 1
+	}
+
+	ring := make([]int, size)
+	for i := range size {
+		ring[i] = i
+	}
+
+	rounds := make([][]edge, 0, size-1)
+	for range size - 1 {
+		round := make([]edge, 0, size/2)
+		for i := range size / 2 {
+			a := ring[i]
+			b := ring[size-1-i]
+			if a >= n || b >= n {
+				continue
+			}
+			if a > b {
+				a, b = b, a
+			}
+			round = append(round, edge{P1: Node(a), P2: Node(b)})
+		}
+		rounds = append(rounds, round)
+
+		last := ring[size-1]
+		copy(ring[2:], ring[1:size-1])
+		ring[1] = last
+	}
+
+	return rounds
+}
+
+// Returns a k-regular bipartite graph between left and right, that must have
+// the same number of nodes: the i-th node of left is matched with the k nodes
+// of right that follow the i-th one.
+func makeBipartiteMatching(left, right []int, k int) matching {
+	res := make(matching)
+	if len(left) != len(right) || k > len(left) {
+		return res
+	}
+
+	for i := range left {
+		for count := range k {
+			res.addCanonicalEdge(left[i], right[(i+count)%len(right)])
+		}
+	}
+	return res
+}
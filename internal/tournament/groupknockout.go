@@ -0,0 +1,261 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// GroupKnockout is played in two stages: teams play a round robin within their
+// group, then the best Qualifiers teams of every group play a knockout
+// bracket. Group matches have stage StageGroup, bracket matches StageMain.
+type GroupKnockout struct {
+	Name        string
+	DateStart   time.Time
+	Groups      [][]Team
+	Qualifiers  int
+	Rounds      []Round
+	Elimination EliminationOptions
+}
+
+func (gk *GroupKnockout) GetName() string {
+	return gk.Name
+}
+
+func (gk *GroupKnockout) GetDateStart() time.Time {
+	return gk.DateStart
+}
+
+// GetTeams returns the teams of every group, group after group.
+func (gk *GroupKnockout) GetTeams() []Team {
+	var teams []Team
+	for _, group := range gk.Groups {
+		teams = append(teams, group...)
+	}
+	return teams
+}
+
+func (gk *GroupKnockout) GetRounds() []Round {
+	return gk.Rounds
+}
+
+func (gk *GroupKnockout) GetGroups() [][]Team {
+	return gk.Groups
+}
+
+func NewGroupKnockout(
+	name string,
+	dateStart time.Time,
+	groups [][]Team,
+	qualifiers int,
+	rounds []Round,
+) *GroupKnockout {
+	return &GroupKnockout{
+		Name:       name,
+		DateStart:  dateStart,
+		Groups:     groups,
+		Qualifiers: qualifiers,
+		Rounds:     rounds,
+	}
+}
+
+func MakeGroupKnockout(
+	name string,
+	dateStart time.Time,
+	groups [][]Team,
+	qualifiers int,
+	rounds []Round,
+) GroupKnockout {
+	return GroupKnockout{
+		Name:       name,
+		DateStart:  dateStart,
+		Groups:     groups,
+		Qualifiers: qualifiers,
+		Rounds:     rounds,
+	}
+}
+
+func (gk *GroupKnockout) GetTournamentType() TournamentType {
+	return TournamentTypeGroupKnockout
+}
+
+func (gk GroupKnockout) GetResting(round int, separator string) []string {
+	if round > len(gk.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range gk.GetTeams() {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range gk.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (gk *GroupKnockout) GetEliminationOptions() EliminationOptions {
+	return gk.Elimination
+}
+
+// IsGroupStageOver tells whether every group match has been completed.
+func (gk *GroupKnockout) IsGroupStageOver() bool {
+	for _, r := range stageRounds(gk.Rounds, StageGroup) {
+		for _, m := range r.Matches {
+			if m.MatchStatus != MatchCompleted {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// GetGroupStandings returns the standings of every group.
+func (gk *GroupKnockout) GetGroupStandings() [][]TeamStanding {
+	groupRounds := stageRounds(gk.Rounds, StageGroup)
+
+	res := make([][]TeamStanding, len(gk.Groups))
+	for i, group := range gk.Groups {
+		res[i] = GetTeamStandings(group, groupRounds)
+	}
+	return res
+}
+
+// Returns the qualified teams sorted by seed: group winners first, then
+// runners-up and so on, in group order. With this order the bracket does not
+// pair two teams of the same group in its first round.
+func (gk *GroupKnockout) getQualified() []*Team {
+	standings := gk.GetGroupStandings()
+
+	var qualified []*Team
+	for position := range gk.Qualifiers {
+		for _, group := range standings {
+			if position < len(group) {
+				qualified = append(qualified, &group[position].Team)
+			}
+		}
+	}
+	return qualified
+}
+
+func groupName(group int) string {
+	return string(rune('A' + group))
+}
+
+// GetBracket returns the knockout bracket. While the group stage is being
+// played, the first round only tells which group position fills each slot.
+func (gk *GroupKnockout) GetBracket() [][]BracketMatch {
+	bracketRounds := stageRounds(gk.Rounds, StageMain)
+
+	if gk.IsGroupStageOver() {
+		return makeBracket(makeBracketEntrants(gk.getQualified()), bracketRounds)
+	}
+
+	var placeholders []*Team
+	sources := make(map[*Team]string)
+	for position := range gk.Qualifiers {
+		for group := range gk.Groups {
+			t := &Team{}
+			placeholders = append(placeholders, t)
+			sources[t] = fmt.Sprintf("%d° %s", position+1, groupName(group))
+		}
+	}
+
+	bracket := makeBracket(makeBracketEntrants(placeholders), nil)
+	if len(bracket) > 0 {
+		for i := range bracket[0] {
+			bm := &bracket[0][i]
+			bm.SourceA, bm.TeamA = sources[bm.TeamA], nil
+			if bm.TeamB != nil {
+				bm.SourceB, bm.TeamB = sources[bm.TeamB], nil
+			}
+		}
+	}
+	return bracket
+}
+
+// GetConsolationBracket returns the bracket of the qualified teams that lost
+// their first knockout match, nil until they are all known.
+func (gk *GroupKnockout) GetConsolationBracket() [][]BracketMatch {
+	if !gk.Elimination.Consolation || !gk.IsGroupStageOver() {
+		return nil
+	}
+	return makeConsolationBracket(gk.getQualified(), gk.Rounds)
+}
+
+// GetThirdPlaceMatch returns the match for the third place, nil when it has
+// not been scheduled.
+func (gk *GroupKnockout) GetThirdPlaceMatch() *Match {
+	return thirdPlaceMatch(gk.Rounds)
+}
+
+type TeamStanding struct {
+	Team          Team `json:"team"`
+	Played        int  `json:"played"`
+	Won           int  `json:"won"`
+	PointsFor     int  `json:"pointsFor"`
+	PointsAgainst int  `json:"pointsAgainst"`
+}
+
+// GetTeamStandings ranks the teams by the completed matches they played
+// against each other in the given rounds. Teams are ranked by wins, then by
+// points difference, then by points scored; remaining ties keep the order of
+// teams.
+func GetTeamStandings(teams []Team, rounds []Round) []TeamStanding {
+	standings := make([]TeamStanding, len(teams))
+	index := make(map[Team]int)
+	for i, t := range teams {
+		standings[i] = TeamStanding{Team: t}
+		index[t] = i
+	}
+
+	for _, round := range rounds {
+		for _, m := range round.Matches {
+			if m.MatchStatus != MatchCompleted {
+				continue
+			}
+			a, okA := index[*m.TeamA]
+			b, okB := index[*m.TeamB]
+			if !okA || !okB {
+				continue
+			}
+
+			standings[a].Played += 1
+			standings[a].PointsFor += m.ScoreA
+			standings[a].PointsAgainst += m.ScoreB
+			standings[b].Played += 1
+			standings[b].PointsFor += m.ScoreB
+			standings[b].PointsAgainst += m.ScoreA
+
+			if winner := m.Winner(); winner != nil {
+				standings[index[*winner]].Won += 1
+			}
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		si, sj := standings[i], standings[j]
+		if si.Won != sj.Won {
+			return si.Won > sj.Won
+		}
+		di, dj := si.PointsFor-si.PointsAgainst, sj.PointsFor-sj.PointsAgainst
+		if di != dj {
+			return di > dj
+		}
+		return si.PointsFor > sj.PointsFor
+	})
+
+	return standings
+}
@@ -0,0 +1,194 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+type GroupKnockoutFactory struct {
+	Name            string
+	AvailableCourts int
+	GroupsNumber    int
+	Qualifiers      int
+	Elimination     EliminationOptions
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewGroupKnockoutFactory(availableCourts, groupsNumber, qualifiers int) *GroupKnockoutFactory {
+	return &GroupKnockoutFactory{
+		AvailableCourts: availableCourts,
+		GroupsNumber:    groupsNumber,
+		Qualifiers:      qualifiers,
+	}
+}
+
+// Splits the teams, sorted by seed, in groups following a snake order: with 3
+// groups the first 6 seeds go to A, B, C, C, B, A. Groups sizes differ by at
+// most one team and each group gets teams of similar strength.
+func makeGroups(teams []Team, groupsNumber int) [][]Team {
+	groups := make([][]Team, groupsNumber)
+	for i, t := range teams {
+		g := i % groupsNumber
+		if (i/groupsNumber)%2 == 1 {
+			g = groupsNumber - 1 - g
+		}
+		groups[g] = append(groups[g], t)
+	}
+	return groups
+}
+
+// MakeTournament draws the groups and schedules the whole group stage. The
+// bracket is generated with MakeNextRound once the group stage is over.
+func (gf *GroupKnockoutFactory) MakeTournament(
+	ctx context.Context,
+	name string,
+	teams []Team,
+	dateStart time.Time,
+) (*GroupKnockout, error) {
+
+	if gf.AvailableCourts <= 0 {
+		return nil, errors.New("at least one court is needed to generate a round")
+	}
+	if gf.GroupsNumber <= 0 {
+		return nil, errors.New("at least one group is needed")
+	}
+	if len(teams) < 2*gf.GroupsNumber {
+		return nil, fmt.Errorf(
+			"%d teams are not enough for %d groups, every group needs at least two teams",
+			len(teams),
+			gf.GroupsNumber,
+		)
+	}
+
+	groups := makeGroups(teams, gf.GroupsNumber)
+
+	smallestGroup := len(groups[len(groups)-1])
+	for _, g := range groups {
+		smallestGroup = min(smallestGroup, len(g))
+	}
+	if gf.Qualifiers <= 0 || gf.Qualifiers > smallestGroup {
+		return nil, fmt.Errorf(
+			"the number of qualified teams per group must be between 1 and %d",
+			smallestGroup,
+		)
+	}
+	if gf.Qualifiers*gf.GroupsNumber < 2 {
+		return nil, errors.New("at least two teams must qualify for the knockout stage")
+	}
+
+	gk := NewGroupKnockout(name, dateStart, groups, gf.Qualifiers, nil)
+	gk.Elimination = gf.Elimination
+
+	rounds, err := gf.scheduleGroups(ctx, groups)
+	if err != nil {
+		return nil, err
+	}
+	balanceCourts(rounds, 0, gf.CourtWeights)
+	gk.Rounds = rounds
+
+	return gk, nil
+}
+
+// Schedules the round robin of every group, running the groups in parallel
+// on the available courts. Matches are assigned to rounds with the rodeo
+// backtracking solver, looking for the fewest rounds possible.
+func (gf *GroupKnockoutFactory) scheduleGroups(
+	ctx context.Context,
+	groups [][]Team,
+) ([]Round, error) {
+
+	var teams []Team
+	graph := MakeGraph()
+	minRounds := 0
+
+	for _, group := range groups {
+		offset := len(teams)
+		teams = append(teams, group...)
+
+		for i := range group {
+			for j := i + 1; j < len(group); j++ {
+				graph.AddEdge(edge{Node(offset + i), Node(offset + j)})
+			}
+		}
+
+		// A round robin of n teams needs n-1 rounds, n when n is odd.
+		minRounds = max(minRounds, len(group)-1+len(group)%2)
+	}
+
+	totalMatches := graph.Size()
+	minRounds = max(minRounds, int(math.Ceil(float64(totalMatches)/float64(gf.AvailableCourts))))
+
+	rf := RodeoFactory{AvailableCourts: gf.AvailableCourts}
+
+	for roundsNumber := minRounds; roundsNumber <= totalMatches; roundsNumber++ {
+		matchesPerTurn := float64(totalMatches) / float64(roundsNumber)
+
+		solution, err := rf.makeMatchings(
+			ctx,
+			graph,
+			len(teams),
+			uniformRoundSizes(matchesPerTurn, roundsNumber),
+		)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("group stage scheduling timed out: %w", ctx.Err())
+			}
+			continue
+		}
+
+		var rounds []Round
+		for _, m := range solution {
+			if len(m) == 0 {
+				continue
+			}
+
+			edges := make([]edge, 0, len(m))
+			for e := range m {
+				edges = append(edges, e)
+			}
+			sort.Slice(edges, func(i, j int) bool { return edges[i].P1 < edges[j].P1 })
+
+			matches := make([]Match, len(edges))
+			for i, e := range edges {
+				matches[i] = Match{
+					TeamA:   &teams[e.P1],
+					TeamB:   &teams[e.P2],
+					CourtId: i + 1,
+					Stage:   StageGroup,
+				}
+			}
+			rounds = append(rounds, Round{Matches: matches})
+		}
+
+		return rounds, nil
+	}
+
+	return nil, errors.New("could not schedule the group stage with the given parameters")
+}
+
+// MakeNextRound generates the next round of the knockout stage, with the
+// consolation bracket and the third place match when enabled. The group stage
+// and the last bracket round must be over.
+func (gf *GroupKnockoutFactory) MakeNextRound(gk *GroupKnockout) (Round, error) {
+	if !gk.IsGroupStageOver() {
+		return Round{}, errors.New("the group stage is not over: some matches have no result yet")
+	}
+
+	round, err := nextEliminationRound(
+		gk.getQualified(),
+		stageRounds(gk.Rounds, StageMain, StageConsolation, StageThirdPlace),
+		gf.AvailableCourts,
+		gk.Elimination,
+	)
+	if err != nil {
+		return Round{}, err
+	}
+	balanceNextRoundCourts(gk.Rounds, round, gf.CourtWeights)
+
+	return round, nil
+}
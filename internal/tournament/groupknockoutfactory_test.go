@@ -0,0 +1,122 @@
+package tournament
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMakeGroups(t *testing.T) {
+	teams := makeTeams(7)
+	groups := makeGroups(teams, 3)
+
+	expected := [][]Team{
+		{teams[0], teams[5], teams[6]},
+		{teams[1], teams[4]},
+		{teams[2], teams[3]},
+	}
+	for i := range expected {
+		if len(groups[i]) != len(expected[i]) {
+			t.Fatalf("expected group %d to be %v, got %v", i, expected[i], groups[i])
+		}
+		for j := range expected[i] {
+			if groups[i][j] != expected[i][j] {
+				t.Errorf("expected group %d to be %v, got %v", i, expected[i], groups[i])
+			}
+		}
+	}
+}
+
+func TestMakeGroupKnockout(t *testing.T) {
+
+	teams := makeTeams(10)
+	factory := GroupKnockoutFactory{AvailableCourts: 4, GroupsNumber: 2, Qualifiers: 2}
+
+	gk, err := factory.MakeTournament(context.Background(), "groups", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building tournament: %v", err)
+	}
+
+	t.Run("Assertion_1_EveryGroupPlaysRoundRobin", func(t *testing.T) {
+		played := make(map[[2]Team]int)
+		for r, round := range gk.Rounds {
+			if len(round.Matches) > factory.AvailableCourts {
+				t.Errorf("round %d has %d matches, only %d courts", r, len(round.Matches), factory.AvailableCourts)
+			}
+			playing := make(map[Team]bool)
+			for _, m := range round.Matches {
+				if m.Stage != StageGroup {
+					t.Errorf("expected a group match, got stage %d", m.Stage)
+				}
+				if playing[*m.TeamA] || playing[*m.TeamB] {
+					t.Errorf("a team plays twice in round %d", r)
+				}
+				playing[*m.TeamA], playing[*m.TeamB] = true, true
+				played[[2]Team{*m.TeamA, *m.TeamB}]++
+				played[[2]Team{*m.TeamB, *m.TeamA}]++
+			}
+		}
+
+		for _, group := range gk.Groups {
+			for i := range group {
+				for j := i + 1; j < len(group); j++ {
+					if played[[2]Team{group[i], group[j]}] != 1 {
+						t.Errorf("expected %v and %v to play once", group[i], group[j])
+					}
+				}
+			}
+		}
+		if len(played) != 2*20 {
+			t.Errorf("expected 20 group matches, got %d", len(played)/2)
+		}
+	})
+
+	t.Run("Assertion_2_BracketShowsGroupPositions", func(t *testing.T) {
+		bracket := gk.GetBracket()
+		if len(bracket) != 2 || bracket[0][0].SourceA != "1° A" || bracket[0][0].SourceB != "2° B" {
+			t.Errorf("expected the first semifinal to be 1° A against 2° B, got %+v", bracket)
+		}
+		if _, err := factory.MakeNextRound(gk); err == nil {
+			t.Errorf("expected an error while the group stage is being played")
+		}
+	})
+
+	for i := range gk.Rounds {
+		completeBySeed(&gk.Rounds[i], teams)
+	}
+
+	t.Run("Assertion_3_GroupWinnersAvoidEachOther", func(t *testing.T) {
+		round, err := factory.MakeNextRound(gk)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building next round: %v", err)
+		}
+		if len(round.Matches) != 2 {
+			t.Fatalf("expected 2 semifinals, got %d", len(round.Matches))
+		}
+		// Group A holds seeds 1, 4, 5, 8, 9 and group B seeds 2, 3, 6, 7, 10.
+		expected := [][2]Team{{teams[0], teams[2]}, {teams[1], teams[3]}}
+		for i, m := range round.Matches {
+			if m.Stage != StageMain || *m.TeamA != expected[i][0] || *m.TeamB != expected[i][1] {
+				t.Errorf("expected semifinal %v, got %v vs %v", expected[i], *m.TeamA, *m.TeamB)
+			}
+		}
+	})
+}
+
+func TestMakeGroupKnockoutInvalid(t *testing.T) {
+	teams := makeTeams(5)
+
+	t.Run("Assertion_1_TooManyGroups", func(t *testing.T) {
+		factory := GroupKnockoutFactory{AvailableCourts: 2, GroupsNumber: 3, Qualifiers: 1}
+		if _, err := factory.MakeTournament(context.Background(), "groups", teams, time.Now()); err == nil {
+			t.Errorf("expected an error with groups of less than two teams")
+		}
+	})
+
+	t.Run("Assertion_2_TooManyQualifiers", func(t *testing.T) {
+		factory := GroupKnockoutFactory{AvailableCourts: 2, GroupsNumber: 2, Qualifiers: 3}
+		if _, err := factory.MakeTournament(context.Background(), "groups", teams, time.Now()); err == nil {
+			t.Errorf("expected an error when more teams qualify than a group holds")
+		}
+	})
+}
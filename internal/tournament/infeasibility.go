@@ -0,0 +1,263 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type InfeasibilityReason string
+
+const (
+	TooFewCourts     InfeasibilityReason = "TooFewCourts"
+	TooFewPlayers    InfeasibilityReason = "TooFewPlayers"
+	TooFewRounds     InfeasibilityReason = "TooFewRounds"
+	OddParticipation InfeasibilityReason = "OddParticipation"
+	GenderSplit      InfeasibilityReason = "GenderSplit"
+	SolverTimeout    InfeasibilityReason = "SolverTimeout"
+	// The constraints, availability or other options cannot be followed.
+	Unsatisfiable InfeasibilityReason = "Unsatisfiable"
+)
+
+// Infeasibility tells why a tournament cannot be generated, and which changes
+// of its parameters would make it possible.
+type Infeasibility struct {
+	Reason      InfeasibilityReason `json:"reason"`
+	Message     string              `json:"message"`
+	Suggestions []string            `json:"suggestions"`
+}
+
+func (i *Infeasibility) Error() string {
+	return i.Message
+}
+
+var errNoMatchings = errors.New("could not find valid matchings with the given parameters")
+
+// Rounds added at most by the suggestions.
+const maxSuggestedRounds = 3
+
+// Suggests the smallest changes of rounds, courts and participants, one at a
+// time, for which feasible holds.
+func suggestChanges(
+	feasible func(participants, rounds, courts int) bool,
+	participants, rounds, courts int,
+	participant string,
+) []string {
+
+	var res []string
+	for extra := 1; extra <= maxSuggestedRounds; extra++ {
+		if feasible(participants, rounds+extra, courts) {
+			res = append(res, "add "+pluralize(extra, "round", "rounds"))
+			break
+		}
+	}
+	if rounds > 1 && feasible(participants, rounds-1, courts) {
+		res = append(res, "remove one round")
+	}
+	for c := courts + 1; c <= max(participants/2, courts+1); c++ {
+		if feasible(participants, rounds, c) {
+			res = append(res, fmt.Sprintf("use %d courts", c))
+			break
+		}
+	}
+	if feasible(participants+1, rounds, courts) {
+		res = append(res, fmt.Sprintf("add one %s", participant))
+	}
+	if participants > 1 && feasible(participants-1, rounds, courts) {
+		res = append(res, fmt.Sprintf("remove one %s", participant))
+	}
+	return res
+}
+
+// Counts n things, e.g. "1 round" or "2 rounds".
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, plural)
+}
+
+// Tells why the solver failed, from the error it returned.
+func solverInfeasibility(err error, suggestions []string) *Infeasibility {
+	var infeasibility *Infeasibility
+	if errors.As(err, &infeasibility) {
+		return infeasibility
+	}
+	if errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, errNoMatchings) {
+		return &Infeasibility{
+			Reason:      SolverTimeout,
+			Message:     "no schedule was found in time with these parameters",
+			Suggestions: append(suggestions, "try again"),
+		}
+	}
+	return &Infeasibility{Reason: Unsatisfiable, Message: err.Error()}
+}
+
+func rodeoFeasible(teams, rounds, courts int) bool {
+	totalMatches, _, _ := getMatchesPerTeam(teams, rounds, courts)
+	return totalMatches > 0
+}
+
+// Diagnose tells why a rodeo of teams cannot be generated, given the error
+// MakeTournament or GetFirstValidTournament returned.
+func (rf *RodeoFactory) Diagnose(teams []Team, err error) *Infeasibility {
+	n := len(teams)
+	if len(rf.Courts) > 0 || len(rf.Availability) > 0 {
+		return solverInfeasibility(err, nil)
+	}
+
+	suggest := func() []string {
+		return suggestChanges(rodeoFeasible, n, rf.MaxRounds, rf.AvailableCourts, "team")
+	}
+	switch {
+	case n < 2:
+		return &Infeasibility{
+			Reason:      TooFewPlayers,
+			Message:     fmt.Sprintf("a rodeo needs at least 2 teams, got %d", n),
+			Suggestions: []string{"add " + pluralize(2-n, "team", "teams")},
+		}
+	case rf.MaxRounds < 1:
+		return &Infeasibility{
+			Reason:      TooFewRounds,
+			Message:     "a rodeo needs at least one round",
+			Suggestions: []string{"add one round"},
+		}
+	case rf.AvailableCourts < 1:
+		return &Infeasibility{
+			Reason:      TooFewCourts,
+			Message:     "a rodeo needs at least one court",
+			Suggestions: []string{"use 1 court"},
+		}
+	case !rodeoFeasible(n, rf.MaxRounds, rf.AvailableCourts):
+		// Matches per team that fit on the courts, but leave a team without
+		// an opponent.
+		for k := min(rf.MaxRounds, n-1); k > 0; k-- {
+			if (n*k)%2 != 0 && float64(n*k)/2/float64(rf.MaxRounds) <= float64(rf.AvailableCourts) {
+				return &Infeasibility{
+					Reason: OddParticipation,
+					Message: fmt.Sprintf(
+						"with %d teams playing %d matches each, one team is left without an opponent",
+						n, k,
+					),
+					Suggestions: suggest(),
+				}
+			}
+		}
+		return &Infeasibility{
+			Reason: TooFewCourts,
+			Message: fmt.Sprintf(
+				"%d courts are not enough for %d teams in %d rounds",
+				rf.AvailableCourts, n, rf.MaxRounds,
+			),
+			Suggestions: suggest(),
+		}
+	default:
+		return solverInfeasibility(err, suggest())
+	}
+}
+
+func singlePlayerRodeoFeasible(people, rounds, courts int) bool {
+	return getMatchesPerPerson(people, rounds, courts).MatchesPerPerson > 0
+}
+
+// Diagnose tells why the single player rodeo cannot be generated, given the
+// error MakeTournament or GetFirstValidTournament returned.
+func (rf *SinglePlayerRodeoFactory) Diagnose(err error) *Infeasibility {
+	n := len(rf.People)
+	if len(rf.Availability) > 0 {
+		return solverInfeasibility(err, nil)
+	}
+
+	suggest := func() []string {
+		return suggestChanges(singlePlayerRodeoFeasible, n, rf.MaxRounds, rf.AvailableCourts, "person")
+	}
+	switch {
+	case n < 4:
+		return &Infeasibility{
+			Reason:      TooFewPlayers,
+			Message:     fmt.Sprintf("a single player rodeo needs at least 4 people, got %d", n),
+			Suggestions: []string{"add " + pluralize(4-n, "person", "people")},
+		}
+	case rf.MaxRounds < 1:
+		return &Infeasibility{
+			Reason:      TooFewRounds,
+			Message:     "a single player rodeo needs at least one round",
+			Suggestions: []string{"add one round"},
+		}
+	case rf.AvailableCourts < 1:
+		return &Infeasibility{
+			Reason:      TooFewCourts,
+			Message:     "a single player rodeo needs at least one court",
+			Suggestions: []string{"use 1 court"},
+		}
+	case !singlePlayerRodeoFeasible(n, rf.MaxRounds, rf.AvailableCourts):
+		return &Infeasibility{
+			Reason: OddParticipation,
+			Message: fmt.Sprintf(
+				"%d people cannot all play the same number of matches in %d rounds on %d courts",
+				n, rf.MaxRounds, rf.AvailableCourts,
+			),
+			Suggestions: suggest(),
+		}
+	}
+
+	if infeasibility := rf.diagnoseGenders(); infeasibility != nil {
+		return infeasibility
+	}
+	return solverInfeasibility(err, suggest())
+}
+
+// Tells whether the genders of the people that rotate partners let them
+// have partners in the pairing mode.
+func (rf *SinglePlayerRodeoFactory) diagnoseGenders() *Infeasibility {
+	_, rotating := rf.fixedPairs()
+	n := len(rf.People)
+
+	pairable := func(rotating []Person, people, rounds int) bool {
+		k := getMatchesPerPerson(people, rounds, rf.AvailableCourts).MatchesPerPerson
+		for ; k > 0; k-- {
+			if (people*k)%4 == 0 && rf.Pairing.canPair(rotating, k) {
+				return true
+			}
+		}
+		return false
+	}
+	if rf.Pairing.validate(rotating, true) == nil && pairable(rotating, n, rf.MaxRounds) {
+		return nil
+	}
+
+	groups := peopleByGender(rotating)
+	var suggestions []string
+	if men, women := len(groups[Male]), len(groups[Female]); rf.Pairing == MixedPairs && men < women {
+		suggestions = append(suggestions, "add "+pluralize(women-men, "man", "men"))
+	} else if rf.Pairing == MixedPairs && women < men {
+		suggestions = append(suggestions, "add "+pluralize(men-women, "woman", "women"))
+	}
+	for _, g := range []Gender{Male, Female} {
+		if rf.Pairing == SameGenderPairs &&
+			pairable(append(slices.Clone(rotating), Person{Gender: g}), n+1, rf.MaxRounds) {
+			suggestions = append(suggestions, fmt.Sprintf("add one %s player", genderName(g)))
+		}
+	}
+	if pairable(rotating, n, rf.MaxRounds+1) {
+		suggestions = append(suggestions, "add one round")
+	}
+
+	message := fmt.Sprintf(
+		"the genders of the people do not let them all have partners in %d rounds",
+		rf.MaxRounds,
+	)
+	if err := rf.Pairing.validate(rotating, true); err != nil {
+		message = err.Error()
+	}
+	return &Infeasibility{Reason: GenderSplit, Message: message, Suggestions: suggestions}
+}
+
+func genderName(g Gender) string {
+	if g == Female {
+		return "female"
+	}
+	return "male"
+}
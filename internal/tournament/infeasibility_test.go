@@ -0,0 +1,66 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDiagnose(t *testing.T) {
+	t.Run("Assertion_1_OddParticipation", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{MaxRounds: 1, AvailableCourts: 3}
+		teams := makeTeams(5)
+		_, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, time.Now())
+		if err == nil {
+			t.Fatalf("expected 5 teams in 1 round to be infeasible")
+		}
+		diagnosis := rodeoFactory.Diagnose(teams, err)
+		if diagnosis.Reason != OddParticipation {
+			t.Errorf("expected %s, got %s", OddParticipation, diagnosis.Reason)
+		}
+		// In 2 rounds the 5 matches do not fit, 2 per round.
+		if !slices.Contains(diagnosis.Suggestions, "add 2 rounds") {
+			t.Errorf("expected to suggest adding 2 rounds, got %v", diagnosis.Suggestions)
+		}
+	})
+
+	t.Run("Assertion_2_TooFewCourts", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{MaxRounds: 1, AvailableCourts: 1}
+		diagnosis := rodeoFactory.Diagnose(makeTeams(8), errors.New("no matches"))
+		if diagnosis.Reason != TooFewCourts {
+			t.Errorf("expected %s, got %s", TooFewCourts, diagnosis.Reason)
+		}
+		if !slices.Contains(diagnosis.Suggestions, "use 4 courts") ||
+			!slices.Contains(diagnosis.Suggestions, "add 3 rounds") {
+			t.Errorf("expected to suggest 4 courts or 3 more rounds, got %v", diagnosis.Suggestions)
+		}
+	})
+
+	t.Run("Assertion_3_GenderSplit", func(t *testing.T) {
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          makeGenderedPeople(5, 3),
+			Pairing:         MixedPairs,
+		}
+		_, err := factory.MakeTournament(context.Background(), "rodeo", time.Now())
+		diagnosis := factory.Diagnose(err)
+		if diagnosis.Reason != GenderSplit {
+			t.Errorf("expected %s, got %s", GenderSplit, diagnosis.Reason)
+		}
+		if !slices.Contains(diagnosis.Suggestions, "add 2 women") {
+			t.Errorf("expected to suggest adding 2 women, got %v", diagnosis.Suggestions)
+		}
+	})
+
+	t.Run("Assertion_4_SolverTimeout", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{MaxRounds: 3, AvailableCourts: 4}
+		err := fmt.Errorf("tournament generation failed or timed out: %w", context.DeadlineExceeded)
+		if diagnosis := rodeoFactory.Diagnose(makeTeams(8), err); diagnosis.Reason != SolverTimeout {
+			t.Errorf("expected %s, got %s", SolverTimeout, diagnosis.Reason)
+		}
+	})
+}
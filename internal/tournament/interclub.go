@@ -0,0 +1,224 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Club takes part in an interclub league with a squad of pairs, sorted from
+// the strongest to the weakest.
+type Club struct {
+	Name  string `json:"name"`
+	Squad []Team `json:"squad"`
+}
+
+// Fixture is the meeting of two clubs in a round of an interclub league. It
+// is made of several pair-vs-pair matches, the rubbers, and it is won by the
+// club that wins more rubbers. Rubbers list the home club's pair as TeamA.
+type Fixture struct {
+	Round   int     `json:"round"`
+	Home    string  `json:"home"`
+	Away    string  `json:"away"`
+	Rubbers []Match `json:"rubbers"`
+}
+
+// IsCompleted tells whether every rubber of the fixture has been completed.
+func (f Fixture) IsCompleted() bool {
+	for _, m := range f.Rubbers {
+		if m.MatchStatus != MatchCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// RubbersWon returns the rubbers won by the home and the away club.
+func (f Fixture) RubbersWon() (int, int) {
+	home, away := 0, 0
+	for _, m := range f.Rubbers {
+		if m.MatchStatus != MatchCompleted || m.ScoreA == m.ScoreB {
+			continue
+		}
+		if m.ScoreA > m.ScoreB {
+			home++
+		} else {
+			away++
+		}
+	}
+	return home, away
+}
+
+// Interclub is a league between clubs: in every round each club meets another
+// club in a fixture, where the pairs of the two squads play against each
+// other.
+type Interclub struct {
+	Name      string
+	DateStart time.Time
+	Clubs     []Club
+	Rounds    []Round
+}
+
+func (ic *Interclub) GetName() string {
+	return ic.Name
+}
+
+func (ic *Interclub) GetDateStart() time.Time {
+	return ic.DateStart
+}
+
+// GetTeams returns the pairs of every squad, club after club.
+func (ic *Interclub) GetTeams() []Team {
+	var teams []Team
+	for _, club := range ic.Clubs {
+		teams = append(teams, club.Squad...)
+	}
+	return teams
+}
+
+func (ic *Interclub) GetRounds() []Round {
+	return ic.Rounds
+}
+
+func (ic *Interclub) GetClubs() []Club {
+	return ic.Clubs
+}
+
+func NewInterclub(name string, dateStart time.Time, clubs []Club, rounds []Round) *Interclub {
+	return &Interclub{
+		Name:      name,
+		DateStart: dateStart,
+		Clubs:     clubs,
+		Rounds:    rounds,
+	}
+}
+
+func MakeInterclub(name string, dateStart time.Time, clubs []Club, rounds []Round) Interclub {
+	return Interclub{
+		Name:      name,
+		DateStart: dateStart,
+		Clubs:     clubs,
+		Rounds:    rounds,
+	}
+}
+
+func (ic *Interclub) GetTournamentType() TournamentType {
+	return TournamentTypeInterclub
+}
+
+func (ic Interclub) GetResting(round int, separator string) []string {
+	if round > len(ic.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range ic.GetTeams() {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range ic.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetFixtures groups the matches of every round by the clubs of the teams
+// that play them.
+func (ic *Interclub) GetFixtures() []Fixture {
+	clubOf := make(map[Team]string)
+	for _, club := range ic.Clubs {
+		for _, t := range club.Squad {
+			clubOf[t] = club.Name
+		}
+	}
+
+	var fixtures []Fixture
+	for r, round := range ic.Rounds {
+		index := make(map[[2]string]int)
+		for _, m := range round.Matches {
+			key := [2]string{clubOf[*m.TeamA], clubOf[*m.TeamB]}
+			i, ok := index[key]
+			if !ok {
+				i = len(fixtures)
+				index[key] = i
+				fixtures = append(fixtures, Fixture{Round: r, Home: key[0], Away: key[1]})
+			}
+			fixtures[i].Rubbers = append(fixtures[i].Rubbers, m)
+		}
+	}
+	return fixtures
+}
+
+type ClubStanding struct {
+	Club           string `json:"club"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	RubbersFor     int    `json:"rubbersFor"`
+	RubbersAgainst int    `json:"rubbersAgainst"`
+}
+
+// GetStandings ranks the clubs by the completed fixtures. Clubs are ranked by
+// fixtures won, then by rubbers difference, then by rubbers won; remaining
+// ties keep the order of clubs.
+func (ic *Interclub) GetStandings() []ClubStanding {
+	standings := make([]ClubStanding, len(ic.Clubs))
+	index := make(map[string]int)
+	for i, club := range ic.Clubs {
+		standings[i] = ClubStanding{Club: club.Name}
+		index[club.Name] = i
+	}
+
+	for _, f := range ic.GetFixtures() {
+		if !f.IsCompleted() {
+			continue
+		}
+		home, away := &standings[index[f.Home]], &standings[index[f.Away]]
+		homeRubbers, awayRubbers := f.RubbersWon()
+
+		home.Played++
+		home.RubbersFor += homeRubbers
+		home.RubbersAgainst += awayRubbers
+		away.Played++
+		away.RubbersFor += awayRubbers
+		away.RubbersAgainst += homeRubbers
+
+		switch {
+		case homeRubbers > awayRubbers:
+			home.Won++
+			away.Lost++
+		case homeRubbers < awayRubbers:
+			away.Won++
+			home.Lost++
+		default:
+			home.Drawn++
+			away.Drawn++
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		si, sj := standings[i], standings[j]
+		if si.Won != sj.Won {
+			return si.Won > sj.Won
+		}
+		di, dj := si.RubbersFor-si.RubbersAgainst, sj.RubbersFor-sj.RubbersAgainst
+		if di != dj {
+			return di > dj
+		}
+		return si.RubbersFor > sj.RubbersFor
+	})
+
+	return standings
+}
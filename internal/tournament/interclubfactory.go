@@ -0,0 +1,108 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type InterclubFactory struct {
+	Name string
+	// Rubbers is the number of pairs every club fields in a fixture.
+	Rubbers int
+	// Legs is 1 when clubs meet once, 2 when they meet home and away.
+	Legs int
+	// DaysBetweenRounds is the number of days between two rounds.
+	DaysBetweenRounds int
+}
+
+func NewInterclubFactory(rubbers, legs, daysBetweenRounds int) *InterclubFactory {
+	return &InterclubFactory{
+		Rubbers:           rubbers,
+		Legs:              legs,
+		DaysBetweenRounds: daysBetweenRounds,
+	}
+}
+
+// MakeTournament schedules the fixtures of the whole league with the circle
+// method, the first round being played on dateStart. In every fixture the
+// i-th pair of a squad plays the i-th pair of the other squad. Each fixture
+// is hosted by the home club, so the rubbers are numbered from court 1.
+func (icf *InterclubFactory) MakeTournament(
+	name string,
+	clubs []Club,
+	dateStart time.Time,
+) (*Interclub, error) {
+
+	if len(clubs) < 2 {
+		return nil, errors.New("an interclub league needs at least two clubs")
+	}
+	if icf.Rubbers <= 0 {
+		return nil, errors.New("a fixture needs at least one rubber")
+	}
+	if icf.Legs != 1 && icf.Legs != 2 {
+		return nil, errors.New("an interclub league is played over one or two legs")
+	}
+	if icf.DaysBetweenRounds <= 0 {
+		return nil, errors.New("rounds must be at least one day apart")
+	}
+
+	names := make(map[string]any)
+	teams := make(map[Team]any)
+	for _, club := range clubs {
+		if _, ok := names[club.Name]; ok || club.Name == "" {
+			return nil, fmt.Errorf("club names must be unique and not empty: %q", club.Name)
+		}
+		names[club.Name] = struct{}{}
+
+		if len(club.Squad) < icf.Rubbers {
+			return nil, fmt.Errorf(
+				"club %s has %d pairs, %d are needed for every fixture",
+				club.Name,
+				len(club.Squad),
+				icf.Rubbers,
+			)
+		}
+		for _, t := range club.Squad {
+			if _, ok := teams[t]; ok {
+				return nil, fmt.Errorf(
+					"team %s - %s plays for more than one club",
+					t.Person1.Id,
+					t.Person2.Id,
+				)
+			}
+			teams[t] = struct{}{}
+		}
+	}
+
+	interclub := NewInterclub(name, dateStart, clubs, nil)
+	schedule := circleMethod(len(clubs))
+
+	for leg := range icf.Legs {
+		for r, edges := range schedule {
+			matches := make([]Match, 0, len(edges)*icf.Rubbers)
+			for i, e := range edges {
+				home, away := &interclub.Clubs[e.P1], &interclub.Clubs[e.P2]
+				// Alternates the home club, and swaps it in the second leg.
+				if (r+i+leg)%2 == 1 {
+					home, away = away, home
+				}
+				for rubber := range icf.Rubbers {
+					matches = append(matches, Match{
+						TeamA:   &home.Squad[rubber],
+						TeamB:   &away.Squad[rubber],
+						CourtId: rubber + 1,
+					})
+				}
+			}
+
+			day := len(interclub.Rounds) * icf.DaysBetweenRounds
+			interclub.Rounds = append(interclub.Rounds, Round{
+				Matches: matches,
+				Date:    dateStart.AddDate(0, 0, day),
+			})
+		}
+	}
+
+	return interclub, nil
+}
@@ -0,0 +1,114 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func makeClubs(n, squadSize int) []Club {
+	teams := makeTeams(n * squadSize)
+	clubs := make([]Club, n)
+	for i := range n {
+		clubs[i] = Club{
+			Name:  fmt.Sprintf("Club%02d", i),
+			Squad: teams[i*squadSize : (i+1)*squadSize],
+		}
+	}
+	return clubs
+}
+
+func TestMakeInterclub(t *testing.T) {
+
+	clubs := makeClubs(4, 3)
+	dateStart := time.Date(2025, time.November, 3, 20, 0, 0, 0, time.UTC)
+	factory := InterclubFactory{Rubbers: 2, Legs: 1, DaysBetweenRounds: 7}
+
+	interclub, err := factory.MakeTournament("interclub", clubs, dateStart)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building interclub: %v", err)
+	}
+
+	t.Run("Assertion_1_EveryClubMeetsOnce", func(t *testing.T) {
+		fixtures := interclub.GetFixtures()
+		if len(fixtures) != 6 {
+			t.Fatalf("expected 6 fixtures, got %d", len(fixtures))
+		}
+		met := make(map[[2]string]bool)
+		for _, f := range fixtures {
+			if len(f.Rubbers) != 2 {
+				t.Errorf("expected 2 rubbers in %s - %s, got %d", f.Home, f.Away, len(f.Rubbers))
+			}
+			if met[[2]string{f.Home, f.Away}] || met[[2]string{f.Away, f.Home}] {
+				t.Errorf("%s and %s meet more than once", f.Home, f.Away)
+			}
+			met[[2]string{f.Home, f.Away}] = true
+		}
+	})
+
+	t.Run("Assertion_2_PairsPlayTheirRubber", func(t *testing.T) {
+		position := make(map[Team]int)
+		for _, club := range clubs {
+			for i, team := range club.Squad {
+				position[team] = i
+			}
+		}
+		for _, f := range interclub.GetFixtures() {
+			for i, m := range f.Rubbers {
+				if position[*m.TeamA] != i || position[*m.TeamB] != i || m.CourtId != i+1 {
+					t.Errorf("rubber %d of %s - %s is played by %v and %v", i+1, f.Home, f.Away, *m.TeamA, *m.TeamB)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_StandingsByClub", func(t *testing.T) {
+		// Club00 wins every rubber, Club01 every rubber but those against
+		// Club00, the rubbers between Club02 and Club03 end in a draw.
+		for r := range interclub.Rounds {
+			for i := range interclub.Rounds[r].Matches {
+				m := &interclub.Rounds[r].Matches[i]
+				m.MatchStatus = MatchCompleted
+				m.ScoreA, m.ScoreB = 3, 3
+				clubA, clubB := clubNumber(clubs, *m.TeamA), clubNumber(clubs, *m.TeamB)
+				if min(clubA, clubB) < 2 {
+					m.ScoreA, m.ScoreB = 6, 2
+					if clubB < clubA {
+						m.ScoreA, m.ScoreB = 2, 6
+					}
+				}
+			}
+		}
+
+		standings := interclub.GetStandings()
+		for i, expected := range []string{"Club00", "Club01"} {
+			if standings[i].Club != expected {
+				t.Errorf("expected %s ranked %d, got %s", expected, i+1, standings[i].Club)
+			}
+		}
+		if standings[0].Won != 3 || standings[0].RubbersFor != 6 {
+			t.Errorf("expected Club00 to win 3 fixtures and 6 rubbers, got %+v", standings[0])
+		}
+		if standings[2].Drawn != 1 || standings[3].Drawn != 1 {
+			t.Errorf("expected Club02 and Club03 to draw their fixture, got %+v", standings[2:])
+		}
+	})
+
+	t.Run("Assertion_4_SquadTooSmall", func(t *testing.T) {
+		factory := InterclubFactory{Rubbers: 4, Legs: 1, DaysBetweenRounds: 7}
+		if _, err := factory.MakeTournament("interclub", clubs, dateStart); err == nil {
+			t.Errorf("expected an error when squads have fewer pairs than rubbers")
+		}
+	})
+}
+
+func clubNumber(clubs []Club, team Team) int {
+	for i, club := range clubs {
+		for _, t := range club.Squad {
+			if t == team {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,109 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// KingOfTheCourt is played on ranked courts, court 1 being the king court:
+// after every round winners move up one court and losers move down one.
+type KingOfTheCourt struct {
+	Name      string
+	DateStart time.Time
+	Teams     []Team
+	Rounds    []Round
+}
+
+func (king *KingOfTheCourt) GetName() string {
+	return king.Name
+}
+
+func (king *KingOfTheCourt) GetDateStart() time.Time {
+	return king.DateStart
+}
+
+func (king *KingOfTheCourt) GetTeams() []Team {
+	return king.Teams
+}
+
+func (king *KingOfTheCourt) GetRounds() []Round {
+	return king.Rounds
+}
+
+func NewKingOfTheCourt(name string, dateStart time.Time, teams []Team, rounds []Round) *KingOfTheCourt {
+	return &KingOfTheCourt{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeKingOfTheCourt(name string, dateStart time.Time, teams []Team, rounds []Round) KingOfTheCourt {
+	return KingOfTheCourt{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (king *KingOfTheCourt) GetTournamentType() TournamentType {
+	return TournamentTypeKingOfTheCourt
+}
+
+func (king KingOfTheCourt) GetResting(round int, separator string) []string {
+	if round > len(king.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range king.Teams {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range king.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetRanking returns the teams ranked by the court they finished on: on each
+// court the winner of the last round ranks before the loser. While the last
+// round is being played, teams are ranked by the court they are playing on.
+func (king *KingOfTheCourt) GetRanking() []Team {
+	if len(king.Rounds) == 0 {
+		return king.Teams
+	}
+
+	matches := sortByCourt(king.Rounds[len(king.Rounds)-1].Matches)
+	ranking := make([]Team, 0, 2*len(matches))
+	for _, m := range matches {
+		if winner, loser := m.Winner(), m.Loser(); winner != nil {
+			ranking = append(ranking, *winner, *loser)
+		} else {
+			ranking = append(ranking, *m.TeamA, *m.TeamB)
+		}
+	}
+	return ranking
+}
+
+// Returns a copy of the matches sorted by court, the king court first.
+func sortByCourt(matches []Match) []Match {
+	res := make([]Match, len(matches))
+	copy(res, matches)
+	sort.SliceStable(res, func(i, j int) bool { return res[i].CourtId < res[j].CourtId })
+	return res
+}
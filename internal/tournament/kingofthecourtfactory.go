@@ -0,0 +1,98 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type KingOfTheCourtFactory struct {
+	Name            string
+	AvailableCourts int
+}
+
+func NewKingOfTheCourtFactory(availableCourts int) *KingOfTheCourtFactory {
+	return &KingOfTheCourtFactory{
+		AvailableCourts: availableCourts,
+	}
+}
+
+// MakeTournament places the teams, sorted by seed, two by two on the courts:
+// the first two seeds start on the king court. Every court must be used, so
+// the number of teams must be even and at most twice the available courts.
+func (kf *KingOfTheCourtFactory) MakeTournament(
+	name string,
+	teams []Team,
+	dateStart time.Time,
+) (*KingOfTheCourt, error) {
+
+	if len(teams) < 2 || len(teams)%2 != 0 {
+		return nil, errors.New("king of the court needs an even number of teams, at least two")
+	}
+	if len(teams) > 2*kf.AvailableCourts {
+		return nil, fmt.Errorf(
+			"%d teams need %d courts, only %d are available",
+			len(teams),
+			len(teams)/2,
+			kf.AvailableCourts,
+		)
+	}
+
+	king := NewKingOfTheCourt(name, dateStart, teams, nil)
+
+	matches := make([]Match, 0, len(teams)/2)
+	for i := 0; i < len(teams); i += 2 {
+		matches = append(matches, Match{
+			TeamA:   &king.Teams[i],
+			TeamB:   &king.Teams[i+1],
+			CourtId: i/2 + 1,
+		})
+	}
+	king.Rounds = []Round{{Matches: matches}}
+
+	return king, nil
+}
+
+// MakeNextRound moves the winners of the last round up one court and the
+// losers down one. The winner of the king court and the loser of the last
+// court stay where they are. Every match of the last round needs a winner.
+func (kf *KingOfTheCourtFactory) MakeNextRound(king *KingOfTheCourt) (Round, error) {
+	if len(king.Rounds) == 0 {
+		return Round{}, errors.New("the tournament has no rounds yet")
+	}
+
+	last := sortByCourt(king.Rounds[len(king.Rounds)-1].Matches)
+	courts := len(last)
+
+	winners := make([]*Team, courts)
+	losers := make([]*Team, courts)
+	for i, m := range last {
+		if m.MatchStatus != MatchCompleted {
+			return Round{}, errors.New("the last round is not over: some matches have no result yet")
+		}
+		if winners[i], losers[i] = m.Winner(), m.Loser(); winners[i] == nil {
+			return Round{}, fmt.Errorf("the match on court %d ended in a draw, it needs a winner", m.CourtId)
+		}
+	}
+
+	matches := make([]Match, courts)
+	for c := range courts {
+		var a, b *Team
+
+		if c == 0 {
+			a = winners[0]
+		} else {
+			a = losers[c-1]
+		}
+
+		if c == courts-1 {
+			b = losers[c]
+		} else {
+			b = winners[c+1]
+		}
+
+		matches[c] = Match{TeamA: a, TeamB: b, CourtId: last[c].CourtId}
+	}
+
+	return Round{Matches: matches}, nil
+}
@@ -0,0 +1,65 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMakeKingOfTheCourt(t *testing.T) {
+
+	teams := makeTeams(6)
+	kingFactory := KingOfTheCourtFactory{AvailableCourts: 3}
+
+	king, err := kingFactory.MakeTournament("king", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building king of the court: %v", err)
+	}
+
+	t.Run("Assertion_1_NextRoundNeedsResults", func(t *testing.T) {
+		if _, err := kingFactory.MakeNextRound(king); err == nil {
+			t.Errorf("expected an error when the first round has no results")
+		}
+	})
+
+	completeBySeed(&king.Rounds[0], teams)
+
+	t.Run("Assertion_2_WinnersMoveUpLosersMoveDown", func(t *testing.T) {
+		round, err := kingFactory.MakeNextRound(king)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building next round: %v", err)
+		}
+		expected := [][2]Team{
+			{teams[0], teams[2]},
+			{teams[1], teams[4]},
+			{teams[3], teams[5]},
+		}
+		for i, m := range round.Matches {
+			if m.CourtId != i+1 || *m.TeamA != expected[i][0] || *m.TeamB != expected[i][1] {
+				t.Errorf("Court %d: expected %v, got %v vs %v", i+1, expected[i], *m.TeamA, *m.TeamB)
+			}
+		}
+	})
+
+	t.Run("Assertion_3_RankingFollowsCourts", func(t *testing.T) {
+		ranking := king.GetRanking()
+		for i := range teams {
+			if ranking[i] != teams[i] {
+				t.Errorf("expected %v ranked %d, got %v", teams[i], i+1, ranking[i])
+			}
+		}
+	})
+
+	t.Run("Assertion_4_DrawsNeedAWinner", func(t *testing.T) {
+		king.Rounds[0].Matches[1].ScoreA = king.Rounds[0].Matches[1].ScoreB
+		if _, err := kingFactory.MakeNextRound(king); err == nil {
+			t.Errorf("expected an error when a match ended in a draw")
+		}
+	})
+}
+
+func TestMakeKingOfTheCourtTooManyTeams(t *testing.T) {
+	kingFactory := KingOfTheCourtFactory{AvailableCourts: 2}
+	if _, err := kingFactory.MakeTournament("king", makeTeams(6), time.Now()); err == nil {
+		t.Errorf("expected an error when teams do not fit on the courts")
+	}
+}
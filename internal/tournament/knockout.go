@@ -0,0 +1,120 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Knockout is a single elimination bracket. Teams are sorted by seed, the
+// first one being the favourite. Elimination tells whether a consolation
+// bracket and a third place match are played as well.
+type Knockout struct {
+	Name        string
+	DateStart   time.Time
+	Teams       []Team
+	Rounds      []Round
+	Elimination EliminationOptions
+}
+
+func (knockout *Knockout) GetName() string {
+	return knockout.Name
+}
+
+func (knockout *Knockout) GetDateStart() time.Time {
+	return knockout.DateStart
+}
+
+func (knockout *Knockout) GetTeams() []Team {
+	return knockout.Teams
+}
+
+func (knockout *Knockout) GetRounds() []Round {
+	return knockout.Rounds
+}
+
+func NewKnockout(name string, dateStart time.Time, teams []Team, rounds []Round) *Knockout {
+	return &Knockout{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeKnockout(name string, dateStart time.Time, teams []Team, rounds []Round) Knockout {
+	return Knockout{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (knockout *Knockout) GetTournamentType() TournamentType {
+	return TournamentTypeKnockout
+}
+
+// GetResting returns the teams that do not play in the given round, either
+// because they advance with a bye or because they have been eliminated.
+func (knockout Knockout) GetResting(round int, separator string) []string {
+	if round > len(knockout.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range knockout.Teams {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range knockout.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (knockout *Knockout) getSeeded() []*Team {
+	seeded := make([]*Team, len(knockout.Teams))
+	for i := range knockout.Teams {
+		seeded[i] = &knockout.Teams[i]
+	}
+	return seeded
+}
+
+func (knockout *Knockout) GetEliminationOptions() EliminationOptions {
+	return knockout.Elimination
+}
+
+// GetBracket returns the bracket round by round, up to the final.
+func (knockout *Knockout) GetBracket() [][]BracketMatch {
+	return makeBracket(
+		makeBracketEntrants(knockout.getSeeded()),
+		stageRounds(knockout.Rounds, StageMain),
+	)
+}
+
+// GetConsolationBracket returns the bracket of the teams that lost their first
+// match, nil until they are all known.
+func (knockout *Knockout) GetConsolationBracket() [][]BracketMatch {
+	if !knockout.Elimination.Consolation {
+		return nil
+	}
+	return makeConsolationBracket(knockout.getSeeded(), knockout.Rounds)
+}
+
+// GetThirdPlaceMatch returns the match for the third place, nil when it has
+// not been scheduled.
+func (knockout *Knockout) GetThirdPlaceMatch() *Match {
+	return thirdPlaceMatch(knockout.Rounds)
+}
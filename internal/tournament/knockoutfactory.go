@@ -0,0 +1,68 @@
+package tournament
+
+import (
+	"errors"
+	"time"
+)
+
+type KnockoutFactory struct {
+	Name            string
+	AvailableCourts int
+	Elimination     EliminationOptions
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewKnockoutFactory(availableCourts int) *KnockoutFactory {
+	return &KnockoutFactory{
+		AvailableCourts: availableCourts,
+	}
+}
+
+// MakeTournament draws the bracket from the teams, sorted by seed, and
+// generates its first round. When the number of teams is not a power of two,
+// the best seeds advance to the second round with a bye.
+func (kf *KnockoutFactory) MakeTournament(
+	name string,
+	teams []Team,
+	dateStart time.Time,
+) (*Knockout, error) {
+
+	if len(teams) < 2 {
+		return nil, errors.New("a knockout needs at least two teams")
+	}
+
+	knockout := NewKnockout(name, dateStart, teams, nil)
+	knockout.Elimination = kf.Elimination
+
+	firstRound, err := nextBracketRound(
+		makeBracketEntrants(knockout.getSeeded()),
+		nil,
+		kf.AvailableCourts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	balanceNextRoundCourts(nil, firstRound, kf.CourtWeights)
+	knockout.Rounds = []Round{firstRound}
+
+	return knockout, nil
+}
+
+// MakeNextRound pairs the winners of the last round, or returns an error when
+// some of its matches have not been completed. The consolation bracket and
+// the third place match are scheduled along, when the knockout has them.
+func (kf *KnockoutFactory) MakeNextRound(knockout *Knockout) (Round, error) {
+	round, err := nextEliminationRound(
+		knockout.getSeeded(),
+		knockout.Rounds,
+		kf.AvailableCourts,
+		knockout.Elimination,
+	)
+	if err != nil {
+		return Round{}, err
+	}
+	balanceNextRoundCourts(knockout.Rounds, round, kf.CourtWeights)
+
+	return round, nil
+}
@@ -0,0 +1,333 @@
+package tournament
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func makeTeams(n int) []Team {
+	teams := make([]Team, n)
+	for i := range n {
+		teams[i] = MakeTeam(
+			Person{Id: fmt.Sprintf("Team%02d_P1", i)},
+			Person{Id: fmt.Sprintf("Team%02d_P2", i)},
+			Male,
+		)
+	}
+	return teams
+}
+
+// Completes every match of the round, the better seed always wins.
+func completeBySeed(round *Round, teams []Team) {
+	seed := make(map[Team]int)
+	for i, t := range teams {
+		seed[t] = i
+	}
+	for i := range round.Matches {
+		m := &round.Matches[i]
+		m.MatchStatus = MatchCompleted
+		if seed[*m.TeamA] < seed[*m.TeamB] {
+			m.ScoreA, m.ScoreB = 6, 2
+		} else {
+			m.ScoreA, m.ScoreB = 2, 6
+		}
+	}
+}
+
+func TestBracketSeeds(t *testing.T) {
+	expected := []int{0, 7, 3, 4, 1, 6, 2, 5}
+	actual := bracketSeeds(8)
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("Expected %v, received %v", expected, actual)
+		}
+	}
+}
+
+func TestBracketSources(t *testing.T) {
+
+	teams := makeTeams(6)
+	knockoutFactory := KnockoutFactory{AvailableCourts: 2}
+
+	knockout, err := knockoutFactory.MakeTournament("knockout", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building knockout: %v", err)
+	}
+	bracket := knockout.GetBracket()
+
+	t.Run("Assertion_1_UnknownTeamsAreSourced", func(t *testing.T) {
+		semifinal := bracket[1][0]
+		if semifinal.TeamA == nil || *semifinal.TeamA != teams[0] {
+			t.Errorf("expected the top seed in the first semifinal, got %v", semifinal.TeamA)
+		}
+		if semifinal.TeamB != nil || semifinal.SourceB != "Vincente 1.2" {
+			t.Errorf("expected the winner of match 1.2 in the first semifinal, got %+v", semifinal)
+		}
+		final := bracket[2][0]
+		if final.SourceA != "Vincente 2.1" || final.SourceB != "Vincente 2.2" {
+			t.Errorf("expected the winners of the semifinals in the final, got %+v", final)
+		}
+	})
+
+	t.Run("Assertion_2_EliminationMatchesNeedAWinner", func(t *testing.T) {
+		if !IsEliminationStage(TournamentTypeKnockout, StageMain) ||
+			!IsEliminationStage(TournamentTypeGroupKnockout, StageThirdPlace) {
+			t.Errorf("expected bracket matches to be elimination matches")
+		}
+		if IsEliminationStage(TournamentTypeGroupKnockout, StageGroup) ||
+			IsEliminationStage(TournamentTypeRodeo, StageMain) {
+			t.Errorf("expected group and rodeo matches to allow draws")
+		}
+	})
+}
+
+func TestMakeKnockoutWithByes(t *testing.T) {
+
+	teams := makeTeams(6)
+	knockoutFactory := KnockoutFactory{AvailableCourts: 2}
+
+	knockout, err := knockoutFactory.MakeTournament("knockout", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building knockout: %v", err)
+	}
+
+	t.Run("Assertion_1_TopSeedsHaveByes", func(t *testing.T) {
+		if len(knockout.Rounds[0].Matches) != 2 {
+			t.Fatalf("expected 2 matches in the first round, got %d", len(knockout.Rounds[0].Matches))
+		}
+		resting := knockout.GetResting(0, "-")
+		expected := []string{"Team00_P1 - Team00_P2", "Team01_P1 - Team01_P2"}
+		if len(resting) != 2 || resting[0] != expected[0] || resting[1] != expected[1] {
+			t.Errorf("expected %v resting, got %v", expected, resting)
+		}
+	})
+
+	t.Run("Assertion_2_NextRoundNeedsResults", func(t *testing.T) {
+		if _, err := knockoutFactory.MakeNextRound(knockout); err == nil {
+			t.Errorf("expected an error when the first round has no results")
+		}
+	})
+
+	for len(knockout.Rounds) < 3 {
+		completeBySeed(&knockout.Rounds[len(knockout.Rounds)-1], teams)
+		round, err := knockoutFactory.MakeNextRound(knockout)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building next round: %v", err)
+		}
+		knockout.Rounds = append(knockout.Rounds, round)
+	}
+
+	t.Run("Assertion_3_FinalBetweenTopSeeds", func(t *testing.T) {
+		final := knockout.Rounds[2].Matches
+		if len(final) != 1 {
+			t.Fatalf("expected a single final match, got %d", len(final))
+		}
+		if *final[0].TeamA != teams[0] || *final[0].TeamB != teams[1] {
+			t.Errorf("expected final between seeds 1 and 2, got %v vs %v", *final[0].TeamA, *final[0].TeamB)
+		}
+	})
+
+	t.Run("Assertion_4_EliminatedTeamsRest", func(t *testing.T) {
+		if resting := knockout.GetResting(2, "-"); len(resting) != 4 {
+			t.Errorf("expected 4 teams resting during the final, got %v", resting)
+		}
+	})
+
+	t.Run("Assertion_5_BracketIsComplete", func(t *testing.T) {
+		completeBySeed(&knockout.Rounds[2], teams)
+		bracket := knockout.GetBracket()
+		if len(bracket) != 3 {
+			t.Fatalf("expected 3 bracket rounds, got %d", len(bracket))
+		}
+		if !bracket[0][0].Bye || *bracket[0][0].TeamA != teams[0] {
+			t.Errorf("expected seed 1 to have a bye, got %+v", bracket[0][0])
+		}
+		if bracket[2][0].Match == nil || *bracket[2][0].Match.Winner() != teams[0] {
+			t.Errorf("expected seed 1 to win the final, got %+v", bracket[2][0])
+		}
+		if _, err := knockoutFactory.MakeNextRound(knockout); err == nil {
+			t.Errorf("expected an error once the bracket is over")
+		}
+	})
+}
+
+func TestMakeKnockoutWithConsolationAndThirdPlace(t *testing.T) {
+
+	teams := makeTeams(8)
+	knockoutFactory := KnockoutFactory{
+		AvailableCourts: 4,
+		Elimination:     EliminationOptions{Consolation: true, ThirdPlace: true},
+	}
+
+	knockout, err := knockoutFactory.MakeTournament("knockout", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building knockout: %v", err)
+	}
+
+	for len(knockout.Rounds) < 3 {
+		completeBySeed(&knockout.Rounds[len(knockout.Rounds)-1], teams)
+		round, err := knockoutFactory.MakeNextRound(knockout)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building next round: %v", err)
+		}
+		knockout.Rounds = append(knockout.Rounds, round)
+	}
+
+	countStages := func(round Round) map[MatchStage]int {
+		res := make(map[MatchStage]int)
+		for _, m := range round.Matches {
+			res[m.Stage]++
+		}
+		return res
+	}
+
+	t.Run("Assertion_1_FirstRoundLosersKeepPlaying", func(t *testing.T) {
+		stages := countStages(knockout.Rounds[1])
+		if stages[StageMain] != 2 || stages[StageConsolation] != 2 {
+			t.Errorf("expected 2 semifinals and 2 consolation matches, got %v", stages)
+		}
+	})
+
+	t.Run("Assertion_2_ThirdPlaceAlongTheFinal", func(t *testing.T) {
+		stages := countStages(knockout.Rounds[2])
+		if stages[StageMain] != 1 || stages[StageThirdPlace] != 1 || stages[StageConsolation] != 1 {
+			t.Errorf("expected final, third place and consolation final, got %v", stages)
+		}
+		third := knockout.GetThirdPlaceMatch()
+		// The loser of the semifinal between seeds 1 and 4 comes first.
+		if third == nil || *third.TeamA != teams[3] || *third.TeamB != teams[2] {
+			t.Errorf("expected third place between seeds 3 and 4, got %+v", third)
+		}
+	})
+
+	t.Run("Assertion_3_ConsolationBracket", func(t *testing.T) {
+		completeBySeed(&knockout.Rounds[2], teams)
+		consolation := knockout.GetConsolationBracket()
+		if len(consolation) != 2 {
+			t.Fatalf("expected 2 consolation rounds, got %d", len(consolation))
+		}
+		if final := consolation[1][0].Match; final == nil || *final.Winner() != teams[4] {
+			t.Errorf("expected seed 5 to win the consolation bracket, got %+v", consolation[1][0])
+		}
+		if _, err := knockoutFactory.MakeNextRound(knockout); err == nil {
+			t.Errorf("expected an error once every bracket is over")
+		}
+	})
+}
+
+func TestMakeKnockoutWithFewerCourtsThanMatches(t *testing.T) {
+
+	teams := makeTeams(8)
+	knockoutFactory := KnockoutFactory{AvailableCourts: 2}
+
+	knockout, err := knockoutFactory.MakeTournament("knockout", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building knockout: %v", err)
+	}
+
+	for {
+		completeBySeed(&knockout.Rounds[len(knockout.Rounds)-1], teams)
+		round, err := knockoutFactory.MakeNextRound(knockout)
+		if err != nil {
+			break
+		}
+		knockout.Rounds = append(knockout.Rounds, round)
+	}
+
+	t.Run("Assertion_1_QuarterfinalsAreSplit", func(t *testing.T) {
+		expected := []int{2, 2, 2, 1}
+		if len(knockout.Rounds) != len(expected) {
+			t.Fatalf("expected %d rounds, got %d", len(expected), len(knockout.Rounds))
+		}
+		for i, round := range knockout.Rounds {
+			if len(round.Matches) != expected[i] {
+				t.Errorf("expected %d matches in round %d, got %d", expected[i], i+1, len(round.Matches))
+			}
+		}
+	})
+
+	t.Run("Assertion_2_NoCourtIsUsedTwice", func(t *testing.T) {
+		for i, round := range knockout.Rounds {
+			courts := make(map[int]bool)
+			for _, m := range round.Matches {
+				if courts[m.CourtId] {
+					t.Errorf("court %d is used twice in round %d", m.CourtId, i+1)
+				}
+				courts[m.CourtId] = true
+			}
+		}
+	})
+
+	t.Run("Assertion_3_BracketFollowsTheSplitRounds", func(t *testing.T) {
+		bracket := knockout.GetBracket()
+		if len(bracket) != 3 {
+			t.Fatalf("expected 3 bracket rounds, got %d", len(bracket))
+		}
+		for _, bm := range bracket[0] {
+			if bm.Match == nil {
+				t.Errorf("expected every quarterfinal to be played, got %+v", bm)
+			}
+		}
+		if bracket[2][0].Match == nil || *bracket[2][0].Match.Winner() != teams[0] {
+			t.Errorf("expected seed 1 to win the final, got %+v", bracket[2][0])
+		}
+	})
+}
+
+func TestMakeKnockoutWithConsolationOnFewCourts(t *testing.T) {
+
+	teams := makeTeams(8)
+	knockoutFactory := KnockoutFactory{
+		AvailableCourts: 2,
+		Elimination:     EliminationOptions{Consolation: true, ThirdPlace: true},
+	}
+
+	knockout, err := knockoutFactory.MakeTournament("knockout", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building knockout: %v", err)
+	}
+
+	for {
+		completeBySeed(&knockout.Rounds[len(knockout.Rounds)-1], teams)
+		round, err := knockoutFactory.MakeNextRound(knockout)
+		if err != nil {
+			break
+		}
+		knockout.Rounds = append(knockout.Rounds, round)
+	}
+
+	t.Run("Assertion_1_RoundsFitOnTheCourts", func(t *testing.T) {
+		for i, round := range knockout.Rounds {
+			if len(round.Matches) > 2 {
+				t.Errorf("expected at most 2 matches in round %d, got %d", i+1, len(round.Matches))
+			}
+			courts := make(map[int]bool)
+			for _, m := range round.Matches {
+				if courts[m.CourtId] {
+					t.Errorf("court %d is used twice in round %d", m.CourtId, i+1)
+				}
+				courts[m.CourtId] = true
+			}
+		}
+	})
+
+	t.Run("Assertion_2_EveryBracketIsPlayed", func(t *testing.T) {
+		played := 0
+		for _, round := range knockout.Rounds {
+			played += len(round.Matches)
+		}
+		// 7 matches of the main bracket, 3 of the consolation and the third
+		// place.
+		if played != 11 {
+			t.Errorf("expected 11 matches, got %d", played)
+		}
+		if knockout.GetThirdPlaceMatch() == nil {
+			t.Errorf("expected the third place match to be played")
+		}
+		consolation := knockout.GetConsolationBracket()
+		if len(consolation) != 2 || consolation[1][0].Match == nil {
+			t.Errorf("expected the consolation final to be played, got %+v", consolation)
+		}
+	})
+}
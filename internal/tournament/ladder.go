@@ -0,0 +1,218 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type ChallengeStatus int
+
+const (
+	ChallengeIssued ChallengeStatus = iota
+	ChallengeAccepted
+	ChallengeCompleted
+	// The challenged team refused an issued challenge.
+	ChallengeDeclined
+	// The challenger called off its challenge before the result.
+	ChallengeWithdrawn
+)
+
+// Challenge is a match of a ladder, where the challenger tries to take the
+// rank of a better ranked team.
+type Challenge struct {
+	Id              int64           `json:"id"`
+	Challenger      Team            `json:"challenger"`
+	Challenged      Team            `json:"challenged"`
+	Status          ChallengeStatus `json:"status"`
+	ScoreChallenger int             `json:"scoreChallenger"`
+	ScoreChallenged int             `json:"scoreChallenged"`
+	IssuedAt        time.Time       `json:"issuedAt"`
+}
+
+// Tells whether the challenge still keeps its teams from other challenges.
+func (c *Challenge) isOpen() bool {
+	return c.Status == ChallengeIssued || c.Status == ChallengeAccepted
+}
+
+func (c *Challenge) involves(team Team) bool {
+	return c.Challenger == team || c.Challenged == team
+}
+
+// Ladder is a season-long competition where teams hold a rank and challenge
+// the teams up to MaxChallengeDistance places above them. When the
+// challenger wins, the two teams swap their ranks. Teams are sorted by rank,
+// the first team is ranked 1.
+type Ladder struct {
+	Id                   int64       `json:"id"`
+	Name                 string      `json:"name"`
+	DateStart            time.Time   `json:"dateStart"`
+	MaxChallengeDistance int         `json:"maxChallengeDistance"`
+	Teams                []Team      `json:"teams"`
+	Challenges           []Challenge `json:"challenges"`
+}
+
+func NewLadder(
+	name string,
+	dateStart time.Time,
+	teams []Team,
+	maxChallengeDistance int,
+) *Ladder {
+	return &Ladder{
+		Name:                 name,
+		DateStart:            dateStart,
+		MaxChallengeDistance: maxChallengeDistance,
+		Teams:                teams,
+	}
+}
+
+// Validate checks that the ladder can host challenges: it needs at least two
+// distinct teams and a positive challenge distance.
+func (l *Ladder) Validate() error {
+	if len(l.Teams) < 2 {
+		return errors.New("a ladder needs at least two teams")
+	}
+	if l.MaxChallengeDistance <= 0 {
+		return errors.New("teams must be allowed to challenge at least one place above")
+	}
+
+	seen := make(map[Team]any)
+	for _, t := range l.Teams {
+		if _, ok := seen[t]; ok {
+			return fmt.Errorf("team %s - %s is in the ladder more than once", t.Person1.Id, t.Person2.Id)
+		}
+		seen[t] = struct{}{}
+	}
+	return nil
+}
+
+// GetRank returns the 1-based rank of team, 0 when it is not in the ladder.
+func (l *Ladder) GetRank(team Team) int {
+	for i, t := range l.Teams {
+		if t == team {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (l *Ladder) getChallenge(challengeId int64) (*Challenge, error) {
+	for i := range l.Challenges {
+		if l.Challenges[i].Id == challengeId {
+			return &l.Challenges[i], nil
+		}
+	}
+	return nil, fmt.Errorf("challenge %d is not part of the ladder", challengeId)
+}
+
+func (l *Ladder) hasOpenChallenge(team Team) bool {
+	for _, c := range l.Challenges {
+		if c.isOpen() && c.involves(team) {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueChallenge adds a challenge of the team ranked challengerRank to the
+// team ranked challengedRank. A team may challenge only the teams up to
+// MaxChallengeDistance places above it, and teams with an open challenge
+// cannot be part of another one.
+func (l *Ladder) IssueChallenge(challengerRank, challengedRank int, issuedAt time.Time) (Challenge, error) {
+	if challengerRank < 1 || challengerRank > len(l.Teams) ||
+		challengedRank < 1 || challengedRank > len(l.Teams) {
+		return Challenge{}, errors.New("ranks must be between 1 and the number of teams")
+	}
+	if challengedRank >= challengerRank {
+		return Challenge{}, errors.New("teams can only challenge better ranked teams")
+	}
+	if challengerRank-challengedRank > l.MaxChallengeDistance {
+		return Challenge{}, fmt.Errorf(
+			"teams can only challenge up to %d places above",
+			l.MaxChallengeDistance,
+		)
+	}
+
+	challenger, challenged := l.Teams[challengerRank-1], l.Teams[challengedRank-1]
+	if l.hasOpenChallenge(challenger) || l.hasOpenChallenge(challenged) {
+		return Challenge{}, errors.New("one of the teams already has an open challenge")
+	}
+
+	challenge := Challenge{
+		Challenger: challenger,
+		Challenged: challenged,
+		Status:     ChallengeIssued,
+		IssuedAt:   issuedAt,
+	}
+	l.Challenges = append(l.Challenges, challenge)
+
+	return challenge, nil
+}
+
+// AcceptChallenge marks an issued challenge as accepted, its result can then
+// be reported.
+func (l *Ladder) AcceptChallenge(challengeId int64) error {
+	c, err := l.getChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if c.Status != ChallengeIssued {
+		return fmt.Errorf("challenge %d is not waiting to be accepted", challengeId)
+	}
+	c.Status = ChallengeAccepted
+	return nil
+}
+
+// DeclineChallenge marks an issued challenge as declined by the challenged
+// team. Both teams can then be part of other challenges.
+func (l *Ladder) DeclineChallenge(challengeId int64) error {
+	c, err := l.getChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if c.Status != ChallengeIssued {
+		return fmt.Errorf("challenge %d can only be declined before it is accepted", challengeId)
+	}
+	c.Status = ChallengeDeclined
+	return nil
+}
+
+// WithdrawChallenge marks an open challenge as withdrawn by the challenger.
+// Both teams can then be part of other challenges.
+func (l *Ladder) WithdrawChallenge(challengeId int64) error {
+	c, err := l.getChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if !c.isOpen() {
+		return fmt.Errorf("challenge %d is not open anymore", challengeId)
+	}
+	c.Status = ChallengeWithdrawn
+	return nil
+}
+
+// ReportResult records the score of an accepted challenge. When the
+// challenger wins, it swaps its rank with the challenged team. Draws are not
+// allowed.
+func (l *Ladder) ReportResult(challengeId int64, scoreChallenger, scoreChallenged int) error {
+	c, err := l.getChallenge(challengeId)
+	if err != nil {
+		return err
+	}
+	if c.Status != ChallengeAccepted {
+		return fmt.Errorf("challenge %d must be accepted before reporting its result", challengeId)
+	}
+	if scoreChallenger == scoreChallenged {
+		return errors.New("a challenge cannot end in a draw")
+	}
+
+	c.ScoreChallenger = scoreChallenger
+	c.ScoreChallenged = scoreChallenged
+	c.Status = ChallengeCompleted
+
+	if scoreChallenger > scoreChallenged {
+		a, b := l.GetRank(c.Challenger)-1, l.GetRank(c.Challenged)-1
+		l.Teams[a], l.Teams[b] = l.Teams[b], l.Teams[a]
+	}
+	return nil
+}
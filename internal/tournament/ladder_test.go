@@ -0,0 +1,137 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLadderChallenges(t *testing.T) {
+
+	teams := makeTeams(5)
+	ladder := NewLadder("ladder", time.Now(), append([]Team{}, teams...), 2)
+	if err := ladder.Validate(); err != nil {
+		t.Fatalf("unexpected error encountered while validating ladder: %v", err)
+	}
+
+	t.Run("Assertion_1_OnlyUpToMaxDistance", func(t *testing.T) {
+		if _, err := ladder.IssueChallenge(5, 2, time.Now()); err == nil {
+			t.Errorf("expected an error when challenging 3 places above")
+		}
+		if _, err := ladder.IssueChallenge(2, 3, time.Now()); err == nil {
+			t.Errorf("expected an error when challenging a worse ranked team")
+		}
+	})
+
+	t.Run("Assertion_2_OneOpenChallengePerTeam", func(t *testing.T) {
+		challenge, err := ladder.IssueChallenge(5, 3, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error encountered while issuing challenge: %v", err)
+		}
+		if challenge.Challenger != teams[4] || challenge.Challenged != teams[2] {
+			t.Errorf("expected %v to challenge %v, got %v", teams[4], teams[2], challenge)
+		}
+		if _, err := ladder.IssueChallenge(4, 3, time.Now()); err == nil {
+			t.Errorf("expected an error when the challenged team has an open challenge")
+		}
+		ladder.Challenges[0].Id = 1
+	})
+
+	t.Run("Assertion_3_ResultNeedsAcceptance", func(t *testing.T) {
+		if err := ladder.ReportResult(1, 6, 4); err == nil {
+			t.Errorf("expected an error when reporting an issued challenge")
+		}
+		if err := ladder.AcceptChallenge(1); err != nil {
+			t.Fatalf("unexpected error encountered while accepting challenge: %v", err)
+		}
+		if err := ladder.ReportResult(1, 4, 4); err == nil {
+			t.Errorf("expected an error when the challenge ends in a draw")
+		}
+	})
+
+	t.Run("Assertion_4_ChallengerWinSwapsRanks", func(t *testing.T) {
+		if err := ladder.ReportResult(1, 6, 4); err != nil {
+			t.Fatalf("unexpected error encountered while reporting result: %v", err)
+		}
+		if ladder.GetRank(teams[4]) != 3 || ladder.GetRank(teams[2]) != 5 {
+			t.Errorf(
+				"expected ranks 3 and 5, got %d and %d",
+				ladder.GetRank(teams[4]),
+				ladder.GetRank(teams[2]),
+			)
+		}
+		if ladder.GetRank(teams[3]) != 4 {
+			t.Errorf("expected the teams in between to keep their rank")
+		}
+	})
+
+	t.Run("Assertion_5_ChallengedWinKeepsRanks", func(t *testing.T) {
+		challenge, err := ladder.IssueChallenge(2, 1, time.Now())
+		if err != nil {
+			t.Fatalf("unexpected error encountered while issuing challenge: %v", err)
+		}
+		ladder.Challenges[1].Id = 2
+		if err := ladder.AcceptChallenge(2); err != nil {
+			t.Fatalf("unexpected error encountered while accepting challenge: %v", err)
+		}
+		if err := ladder.ReportResult(2, 3, 6); err != nil {
+			t.Fatalf("unexpected error encountered while reporting result: %v", err)
+		}
+		if ladder.GetRank(challenge.Challenger) != 2 || ladder.GetRank(challenge.Challenged) != 1 {
+			t.Errorf("expected the ranks not to change when the challenged team wins")
+		}
+	})
+}
+
+func TestLadderClosedChallenges(t *testing.T) {
+
+	teams := makeTeams(3)
+	ladder := NewLadder("ladder", time.Now(), append([]Team{}, teams...), 1)
+
+	t.Run("Assertion_1_DeclinedChallengeIsNotOpen", func(t *testing.T) {
+		if _, err := ladder.IssueChallenge(2, 1, time.Now()); err != nil {
+			t.Fatalf("unexpected error encountered while issuing challenge: %v", err)
+		}
+		ladder.Challenges[0].Id = 1
+		if err := ladder.DeclineChallenge(1); err != nil {
+			t.Fatalf("unexpected error encountered while declining challenge: %v", err)
+		}
+		if err := ladder.AcceptChallenge(1); err == nil {
+			t.Errorf("expected an error when accepting a declined challenge")
+		}
+		if _, err := ladder.IssueChallenge(3, 2, time.Now()); err != nil {
+			t.Errorf("expected the teams of a declined challenge to be free, got %v", err)
+		}
+		ladder.Challenges[1].Id = 2
+	})
+
+	t.Run("Assertion_2_WithdrawnChallengeIsNotOpen", func(t *testing.T) {
+		if err := ladder.AcceptChallenge(2); err != nil {
+			t.Fatalf("unexpected error encountered while accepting challenge: %v", err)
+		}
+		if err := ladder.DeclineChallenge(2); err == nil {
+			t.Errorf("expected an error when declining an accepted challenge")
+		}
+		if err := ladder.WithdrawChallenge(2); err != nil {
+			t.Fatalf("unexpected error encountered while withdrawing challenge: %v", err)
+		}
+		if err := ladder.ReportResult(2, 6, 4); err == nil {
+			t.Errorf("expected an error when reporting a withdrawn challenge")
+		}
+		if err := ladder.WithdrawChallenge(2); err == nil {
+			t.Errorf("expected an error when withdrawing a closed challenge")
+		}
+		if _, err := ladder.IssueChallenge(2, 1, time.Now()); err != nil {
+			t.Errorf("expected the teams of a withdrawn challenge to be free, got %v", err)
+		}
+	})
+}
+
+func TestLadderValidate(t *testing.T) {
+	teams := makeTeams(2)
+	if err := NewLadder("ladder", time.Now(), []Team{teams[0], teams[0]}, 1).Validate(); err == nil {
+		t.Errorf("expected an error when a team is in the ladder twice")
+	}
+	if err := NewLadder("ladder", time.Now(), teams, 0).Validate(); err == nil {
+		t.Errorf("expected an error when teams cannot challenge anyone")
+	}
+}
@@ -0,0 +1,95 @@
+package tournament
+
+import (
+	"sort"
+	"time"
+)
+
+// Mexicano is a single player tournament where only the first round is known
+// in advance: every other round is paired from the standings after the
+// previous one. Teams holds the registered players, one per team.
+type Mexicano struct {
+	Name      string
+	DateStart time.Time
+	Teams     []Team
+	Rounds    []Round
+}
+
+func (mexicano *Mexicano) GetName() string {
+	return mexicano.Name
+}
+
+func (mexicano *Mexicano) GetDateStart() time.Time {
+	return mexicano.DateStart
+}
+
+func (mexicano *Mexicano) GetTeams() []Team {
+	return mexicano.Teams
+}
+
+func (mexicano *Mexicano) GetRounds() []Round {
+	return mexicano.Rounds
+}
+
+func NewMexicano(
+	name string,
+	dateStart time.Time,
+	teams []Team,
+	rounds []Round,
+) *Mexicano {
+	return &Mexicano{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeMexicano(
+	name string,
+	dateStart time.Time,
+	teams []Team,
+	rounds []Round,
+) Mexicano {
+	return Mexicano{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (mexicano *Mexicano) GetTournamentType() TournamentType {
+	return TournamentTypeMexicano
+}
+
+func (mexicano Mexicano) GetResting(round int, separator string) []string {
+	if round > len(mexicano.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	people := make(map[string]any)
+	for _, p := range GetPeople(mexicano.Teams) {
+		people[p.Id] = struct{}{}
+	}
+
+	for _, m := range mexicano.Rounds[round].Matches {
+		delete(people, m.TeamA.Person1.Id)
+		delete(people, m.TeamA.Person2.Id)
+		delete(people, m.TeamB.Person1.Id)
+		delete(people, m.TeamB.Person2.Id)
+	}
+
+	res := make([]string, 0)
+	for id := range people {
+		res = append(res, id)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetStandings returns the players ranked by the points they scored.
+func (mexicano *Mexicano) GetStandings() []PlayerStanding {
+	return GetPlayerStandings(GetPeople(mexicano.Teams), mexicano.Rounds)
+}
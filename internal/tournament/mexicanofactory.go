@@ -0,0 +1,169 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+type MexicanoFactory struct {
+	Name            string
+	AvailableCourts int
+	// People are the players, by their Id.
+	People map[string]Person
+}
+
+func NewMexicanoFactory(participants map[string]Person, availableCourts int) *MexicanoFactory {
+	return &MexicanoFactory{
+		AvailableCourts: availableCourts,
+		People:          participants,
+	}
+}
+
+// MakeTournament registers the players and generates the first round only,
+// following the players' order. Further rounds are built with MakeNextRound
+// once the results of the previous round have been recorded.
+func (mf *MexicanoFactory) MakeTournament(
+	name string,
+	dateStart time.Time,
+) (*Mexicano, error) {
+
+	people := sortPeople(mf.People)
+
+	courts := min(mf.AvailableCourts, len(people)/4)
+	if courts <= 0 {
+		return nil, errors.New(
+			"could not determine valid match parameters. Returning empty tournament",
+		)
+	}
+
+	teams := make([]Team, 0, len(people))
+	for _, p := range people {
+		teams = append(teams, MakeTeam(p, Person{}, Else))
+	}
+
+	firstRound := makeMexicanoRound(people[:4*courts])
+
+	return NewMexicano(name, dateStart, teams, []Round{firstRound}), nil
+}
+
+// MakeNextRound pairs the next round from the current standings: players are
+// split in groups of four following the ranking, and within each group the 1st
+// and 4th play against the 2nd and 3rd. The best group plays on court 1. When
+// there are not enough courts, the players that rested the least sit out.
+func (mf *MexicanoFactory) MakeNextRound(mexicano *Mexicano) (Round, error) {
+
+	rounds := mexicano.GetRounds()
+	if len(rounds) > 0 {
+		for _, m := range rounds[len(rounds)-1].Matches {
+			if m.MatchStatus != MatchCompleted {
+				return Round{}, fmt.Errorf(
+					"round %d has matches without a result, cannot generate the next one",
+					len(rounds),
+				)
+			}
+		}
+	}
+
+	// Players that withdrew keep their standing, but are not paired anymore.
+	registered := make(map[string]any)
+	for _, p := range GetPeople(mexicano.Teams) {
+		registered[p.Id] = struct{}{}
+	}
+	standings := slices.DeleteFunc(mexicano.GetStandings(), func(s PlayerStanding) bool {
+		_, ok := registered[s.Player.Id]
+		return !ok
+	})
+
+	courts := min(mf.AvailableCourts, len(standings)/4)
+	if courts <= 0 {
+		return Round{}, errors.New(
+			"could not determine valid match parameters. Returning empty round",
+		)
+	}
+
+	rank := make(map[string]int)
+	for i, s := range standings {
+		rank[s.Player.Id] = i
+	}
+
+	restingCandidates := make([]PlayerStanding, len(standings))
+	copy(restingCandidates, standings)
+	sort.SliceStable(restingCandidates, func(i, j int) bool {
+		if restingCandidates[i].Played != restingCandidates[j].Played {
+			return restingCandidates[i].Played > restingCandidates[j].Played
+		}
+		return rank[restingCandidates[i].Player.Id] > rank[restingCandidates[j].Player.Id]
+	})
+
+	resting := make(map[string]any)
+	for _, s := range restingCandidates[:len(standings)-4*courts] {
+		resting[s.Player.Id] = struct{}{}
+	}
+
+	playing := make([]Person, 0, 4*courts)
+	for _, s := range standings {
+		if _, ok := resting[s.Player.Id]; !ok {
+			playing = append(playing, s.Player)
+		}
+	}
+
+	return makeMexicanoRound(playing), nil
+}
+
+// Reschedule replaces the players of a mexicano from round fromRound on,
+// numbered from 1, e.g. after a player withdrew or joined late. The rounds
+// before fromRound are kept as they are; when fromRound was already
+// generated, it is paired again from the standings with the new players.
+// fromRound can be the round after the last one, to only change the players.
+func (mf *MexicanoFactory) Reschedule(mexicano *Mexicano, fromRound int, teams []Team) (*Mexicano, error) {
+	if fromRound < 1 || fromRound > len(mexicano.Rounds)+1 {
+		return nil, fmt.Errorf(
+			"round %d is not a round of the tournament, rounds go from 1 to %d",
+			fromRound,
+			len(mexicano.Rounds)+1,
+		)
+	}
+	for i, round := range mexicano.Rounds[fromRound-1:] {
+		for _, m := range round.Matches {
+			if m.MatchStatus == MatchCompleted {
+				return nil, fmt.Errorf(
+					"round %d already has results, reschedule from a later round",
+					fromRound+i,
+				)
+			}
+		}
+	}
+
+	rounds := append([]Round{}, mexicano.Rounds[:fromRound-1]...)
+	res := NewMexicano(mexicano.Name, mexicano.DateStart, teams, rounds)
+	if fromRound > len(mexicano.Rounds) {
+		return res, nil
+	}
+
+	round, err := mf.MakeNextRound(res)
+	if err != nil {
+		return nil, err
+	}
+	res.Rounds = append(res.Rounds, round)
+	return res, nil
+}
+
+func makeMexicanoRound(people []Person) Round {
+	var matches []Match
+
+	for i := 0; i+3 < len(people); i += 4 {
+		teamA := MakeTeam(people[i], people[i+3], Else)
+		teamB := MakeTeam(people[i+1], people[i+2], Else)
+
+		matches = append(matches, Match{
+			TeamA:   &teamA,
+			TeamB:   &teamB,
+			CourtId: i/4 + 1,
+		})
+	}
+
+	return Round{Matches: matches}
+}
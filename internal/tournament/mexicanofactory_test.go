@@ -0,0 +1,167 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMakeMexicano(t *testing.T) {
+
+	mexicanoFactory := MexicanoFactory{
+		AvailableCourts: 2,
+		People:          makePeople(8),
+	}
+
+	mexicano, err := mexicanoFactory.MakeTournament("mexicano", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building mexicano: %v", err)
+	}
+
+	t.Run("Assertion_1_OnlyFirstRoundIsGenerated", func(t *testing.T) {
+		if len(mexicano.Rounds) != 1 {
+			t.Fatalf("expected 1 round, got %d", len(mexicano.Rounds))
+		}
+		if len(mexicano.Rounds[0].Matches) != 2 {
+			t.Errorf("expected 2 matches, got %d", len(mexicano.Rounds[0].Matches))
+		}
+	})
+
+	t.Run("Assertion_2_NextRoundNeedsResults", func(t *testing.T) {
+		if _, err := mexicanoFactory.MakeNextRound(mexicano); err == nil {
+			t.Errorf("expected an error when the last round has no results")
+		}
+	})
+
+	// Player00+Player03 beat Player01+Player02, Player04+Player07 beat
+	// Player05+Player06 by less.
+	results := [][2]int{{20, 4}, {13, 11}}
+	for i := range mexicano.Rounds[0].Matches {
+		m := &mexicano.Rounds[0].Matches[i]
+		m.ScoreA, m.ScoreB = results[i][0], results[i][1]
+		m.MatchStatus = MatchCompleted
+	}
+
+	round, err := mexicanoFactory.MakeNextRound(mexicano)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building next round: %v", err)
+	}
+
+	t.Run("Assertion_3_PairedFromStandings", func(t *testing.T) {
+		top := round.Matches[0]
+		if top.CourtId != 1 {
+			t.Errorf("expected top group on court 1, got %d", top.CourtId)
+		}
+
+		expectedA := MakeTeam(Person{Id: "Player00"}, Person{Id: "Player07"}, Else)
+		expectedB := MakeTeam(Person{Id: "Player03"}, Person{Id: "Player04"}, Else)
+		if *top.TeamA != expectedA || *top.TeamB != expectedB {
+			t.Errorf("expected %v vs %v, got %v vs %v", expectedA, expectedB, *top.TeamA, *top.TeamB)
+		}
+	})
+}
+
+func TestMexicanoRests(t *testing.T) {
+
+	mexicanoFactory := MexicanoFactory{
+		AvailableCourts: 2,
+		People:          makePeople(10),
+	}
+
+	mexicano, err := mexicanoFactory.MakeTournament("mexicano", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building mexicano: %v", err)
+	}
+
+	restedFirst := mexicano.GetResting(0, "-")
+	if len(restedFirst) != 2 {
+		t.Fatalf("expected 2 players resting, got %v", restedFirst)
+	}
+
+	for i := range mexicano.Rounds[0].Matches {
+		m := &mexicano.Rounds[0].Matches[i]
+		m.ScoreA, m.ScoreB = 12, 12
+		m.MatchStatus = MatchCompleted
+	}
+
+	round, err := mexicanoFactory.MakeNextRound(mexicano)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building next round: %v", err)
+	}
+	mexicano.Rounds = append(mexicano.Rounds, round)
+
+	t.Run("Assertion_1_RestedPlayersPlayNext", func(t *testing.T) {
+		restedSecond := make(map[string]any)
+		for _, p := range mexicano.GetResting(1, "-") {
+			restedSecond[p] = struct{}{}
+		}
+		for _, p := range restedFirst {
+			if _, ok := restedSecond[p]; ok {
+				t.Errorf("player %s rested twice in a row", p)
+			}
+		}
+	})
+}
+
+func TestRescheduleMexicano(t *testing.T) {
+
+	mexicanoFactory := MexicanoFactory{
+		AvailableCourts: 2,
+		People:          makePeople(9),
+	}
+
+	mexicano, err := mexicanoFactory.MakeTournament("mexicano", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building mexicano: %v", err)
+	}
+	for i := range mexicano.Rounds[0].Matches {
+		m := &mexicano.Rounds[0].Matches[i]
+		m.ScoreA, m.ScoreB = 16-i, 8+i
+		m.MatchStatus = MatchCompleted
+	}
+	round, err := mexicanoFactory.MakeNextRound(mexicano)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building next round: %v", err)
+	}
+	mexicano.Rounds = append(mexicano.Rounds, round)
+
+	// Player00, who won round 1, withdraws before round 2.
+	withdrawn := Person{Id: "Player00"}
+	teams := mexicano.Teams[1:]
+
+	t.Run("Assertion_1_WithdrawnPlayerNotPaired", func(t *testing.T) {
+		res, err := mexicanoFactory.Reschedule(mexicano, 2, teams)
+		if err != nil {
+			t.Fatalf("reschedule returned an error: %v", err)
+		}
+		if len(res.Rounds) != 2 {
+			t.Fatalf("expected 2 rounds, got %d", len(res.Rounds))
+		}
+		if len(res.Rounds[1].Matches) != 2 {
+			t.Errorf("expected 2 matches in round 2, got %d", len(res.Rounds[1].Matches))
+		}
+		for _, m := range res.Rounds[1].Matches {
+			for _, p := range append(teamPeople(*m.TeamA), teamPeople(*m.TeamB)...) {
+				if p == withdrawn {
+					t.Errorf("expected %s not to play after withdrawing", withdrawn.Id)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_OnlyPlayersChanged", func(t *testing.T) {
+		res, err := mexicanoFactory.Reschedule(mexicano, 3, teams)
+		if err != nil {
+			t.Fatalf("reschedule returned an error: %v", err)
+		}
+		if len(res.Rounds) != 2 || len(res.Teams) != 8 {
+			t.Errorf("expected the 2 rounds kept and 8 players, got %d and %d",
+				len(res.Rounds), len(res.Teams))
+		}
+	})
+
+	t.Run("Assertion_3_RoundWithResults", func(t *testing.T) {
+		if _, err := mexicanoFactory.Reschedule(mexicano, 1, teams); err == nil {
+			t.Errorf("expected an error when rescheduling a round with results")
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package tournament
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+)
+
+// PairingMode tells which people can be partners in single player
+// tournaments, according to their gender.
+type PairingMode int
+
+const (
+	AnyPairs PairingMode = iota
+	// Every team is a man and a woman.
+	MixedPairs
+	// Every team is two people of the same gender.
+	SameGenderPairs
+)
+
+func PairingModeFromString(m string) PairingMode {
+	switch m {
+	case "Mixed":
+		return MixedPairs
+	case "SameGender":
+		return SameGenderPairs
+	default:
+		return AnyPairs
+	}
+}
+
+func (pm PairingMode) allows(a, b Person) bool {
+	switch pm {
+	case MixedPairs:
+		return a.Gender != b.Gender && a.Gender != Else && b.Gender != Else
+	case SameGenderPairs:
+		return a.Gender == b.Gender
+	default:
+		return true
+	}
+}
+
+// Checks that people can be paired at all: their genders must be known.
+// When everyone plays the same number of matches, mixed pairs need as many
+// men as women.
+func (pm PairingMode) validate(people []Person, samePartners bool) error {
+	if pm == AnyPairs {
+		return nil
+	}
+
+	counts := peopleByGender(people)
+	if len(counts[Unknown]) > 0 {
+		return fmt.Errorf(
+			"pairs by gender need the gender of every person, %s has none",
+			people[counts[Unknown][0]].Id,
+		)
+	}
+	if pm != MixedPairs {
+		return nil
+	}
+	if len(counts[Else]) > 0 {
+		return fmt.Errorf(
+			"mixed pairs need the gender of every person, %s is neither male nor female",
+			people[counts[Else][0]].Id,
+		)
+	}
+	if samePartners && len(counts[Male]) != len(counts[Female]) {
+		return fmt.Errorf(
+			"mixed pairs need as many men as women, got %d men and %d women",
+			len(counts[Male]),
+			len(counts[Female]),
+		)
+	}
+	return nil
+}
+
+// Tells whether everyone can have k different partners.
+func (pm PairingMode) canPair(people []Person, k int) bool {
+	if len(people) == 0 {
+		return true
+	}
+	groups := peopleByGender(people)
+	switch pm {
+	case MixedPairs:
+		return len(groups[Male]) == len(groups[Female]) && len(groups[Else]) == 0 &&
+			k <= len(groups[Male])
+	case SameGenderPairs:
+		for _, group := range groups {
+			if k >= len(group) || (k*len(group))%2 != 0 {
+				return false
+			}
+		}
+		return true
+	default:
+		return k < len(people) && (k*len(people))%2 == 0
+	}
+}
+
+// Returns the partners of people, k for each person, that the pairing mode
+// allows, as a graph whose nodes are the indices of people. When given,
+// penalty is minimised over a few tries.
+func (pm PairingMode) makePartners(
+	people []Person,
+	k int,
+	penalty func(a, b int) float64,
+	random *rand.Rand,
+) matching {
+
+	groups := peopleByGender(people)
+	switch pm {
+	case MixedPairs:
+		men, women := groups[Male], slices.Clone(groups[Female])
+		best := makeBipartiteMatching(men, women, k)
+		if penalty == nil {
+			return best
+		}
+		bestPenalty := matchingPenalty(best, penalty)
+		for attempt := 0; attempt < pastPairingAttempts && bestPenalty > 0; attempt++ {
+			random.Shuffle(len(women), func(i, j int) { women[i], women[j] = women[j], women[i] })
+			m := makeBipartiteMatching(men, women, k)
+			if p := matchingPenalty(m, penalty); p < bestPenalty {
+				best, bestPenalty = m, p
+			}
+		}
+		return best
+	case SameGenderPairs:
+		res := make(matching)
+		for _, g := range GetAllGenders() {
+			if len(groups[g]) == 0 {
+				continue
+			}
+			for e := range makeMatchingWithPenalty(groups[g], k, penalty, random) {
+				res[e] = struct{}{}
+			}
+		}
+		return res
+	default:
+		nodes := make([]int, len(people))
+		for i := range people {
+			nodes[i] = i
+		}
+		return makeMatchingWithPenalty(nodes, k, penalty, random)
+	}
+}
+
+func makeMatchingWithPenalty(
+	nodes []int,
+	k int,
+	penalty func(a, b int) float64,
+	random *rand.Rand,
+) matching {
+	if penalty == nil {
+		return makeMatching(nodes, k)
+	}
+	return makeMatchingAvoiding(nodes, k, nil, penalty, random)
+}
+
+// Returns the indices of people by gender.
+func peopleByGender(people []Person) map[Gender][]int {
+	res := make(map[Gender][]int)
+	for i, p := range people {
+		res[p.Gender] = append(res[p.Gender], i)
+	}
+	return res
+}
@@ -0,0 +1,129 @@
+package tournament
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func makeGenderedPeople(men, women int) map[string]Person {
+	people := make(map[string]Person)
+	for i := range men {
+		people[fmt.Sprintf("M%d", i)] = Person{Id: fmt.Sprintf("M%d", i), Gender: Male}
+	}
+	for i := range women {
+		people[fmt.Sprintf("F%d", i)] = Person{Id: fmt.Sprintf("F%d", i), Gender: Female}
+	}
+	return people
+}
+
+// The solver does not always succeed, rodeos are made as the services do,
+// keeping the first of many attempts that succeeds.
+func makeFirstValidRodeo(t *testing.T, factory SinglePlayerRodeoFactory) *SinglePlayerRodeo {
+	rodeo, err := factory.GetFirstValidTournament("rodeo", 10*time.Second, 50, time.Now())
+	if err != nil {
+		t.Fatalf("getFirstValidTournament returned an error: %v", err)
+	}
+	return rodeo
+}
+
+func TestPairingMode(t *testing.T) {
+	t.Run("Assertion_1_MixedPairs", func(t *testing.T) {
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          makeGenderedPeople(4, 4),
+			Pairing:         MixedPairs,
+		}
+		rodeo := makeFirstValidRodeo(t, factory)
+		for i, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				for _, team := range []*Team{m.TeamA, m.TeamB} {
+					if team.Person1.Gender == team.Person2.Gender || team.TeamGender != Else {
+						t.Errorf("Round %d: expected a mixed team, got %v", i+1, *team)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_SameGenderPairs", func(t *testing.T) {
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       5,
+			AvailableCourts: 2,
+			People:          makeGenderedPeople(4, 4),
+			Pairing:         SameGenderPairs,
+		}
+		rodeo := makeFirstValidRodeo(t, factory)
+		for i, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				for _, team := range []*Team{m.TeamA, m.TeamB} {
+					if team.Person1.Gender != team.Person2.Gender || team.TeamGender != team.Person1.Gender {
+						t.Errorf("Round %d: expected a same gender team, got %v", i+1, *team)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_MixedPairsNeedAsManyMenAsWomen", func(t *testing.T) {
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          makeGenderedPeople(5, 3),
+			Pairing:         MixedPairs,
+		}
+		if _, err := factory.MakeTournament(context.Background(), "rodeo", time.Now()); err == nil {
+			t.Errorf("expected an error with 5 men and 3 women")
+		}
+	})
+
+	t.Run("Assertion_4_MixedPairsWithAvailability", func(t *testing.T) {
+		people := makeGenderedPeople(4, 4)
+		factory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          people,
+			Pairing:         MixedPairs,
+			Availability:    []Availability{{Person: Person{Id: "M0", Gender: Male}, FirstRound: 2}},
+		}
+		rodeo := makeFirstValidRodeo(t, factory)
+		for i, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				for _, team := range []*Team{m.TeamA, m.TeamB} {
+					if team.TeamGender != Else {
+						t.Errorf("Round %d: expected a mixed team, got %v", i+1, *team)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_5_GenderNotGiven", func(t *testing.T) {
+		var unknown Person
+		if err := json.Unmarshal([]byte(`{"id": "U0"}`), &unknown); err != nil {
+			t.Fatal(err)
+		}
+		if unknown.Gender != Unknown {
+			t.Fatalf("expected a person without gender to be of unknown gender, got %v", unknown.Gender)
+		}
+
+		for _, pairing := range []PairingMode{MixedPairs, SameGenderPairs} {
+			people := makeGenderedPeople(4, 3)
+			people[unknown.Id] = unknown
+			factory := SinglePlayerRodeoFactory{
+				MaxRounds:       4,
+				AvailableCourts: 2,
+				People:          people,
+				Pairing:         pairing,
+			}
+			_, err := factory.MakeTournament(context.Background(), "rodeo", time.Now())
+			if err == nil || !strings.Contains(err.Error(), "U0 has none") {
+				t.Errorf("pairing mode %d: expected an error for U0, of unknown gender, got %v", pairing, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,133 @@
+package tournament
+
+import (
+	"maps"
+	"math/rand/v2"
+	"slices"
+)
+
+// Number of orders of the nodes tried by makeMatchingAvoiding.
+const pastPairingAttempts = 200
+
+// PairingHistory counts how many times people, identified by their Id, were
+// partners or opponents in past tournaments. A nil history has no pairings.
+type PairingHistory struct {
+	partners  map[[2]string]int
+	opponents map[[2]string]int
+}
+
+func NewPairingHistory() *PairingHistory {
+	return &PairingHistory{
+		partners:  make(map[[2]string]int),
+		opponents: make(map[[2]string]int),
+	}
+}
+
+func personPair(a, b Person) [2]string {
+	if b.Id < a.Id {
+		a, b = b, a
+	}
+	return [2]string{a.Id, b.Id}
+}
+
+func teamPeople(team Team) []Person {
+	var res []Person
+	for _, p := range []Person{team.Person1, team.Person2} {
+		if !p.IsNil() {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// AddMatch records the partners and the opponents of a match.
+func (ph *PairingHistory) AddMatch(teamA, teamB Team) {
+	for _, team := range []Team{teamA, teamB} {
+		if people := teamPeople(team); len(people) == 2 {
+			ph.partners[personPair(people[0], people[1])]++
+		}
+	}
+	for _, a := range teamPeople(teamA) {
+		for _, b := range teamPeople(teamB) {
+			ph.opponents[personPair(a, b)]++
+		}
+	}
+}
+
+// Partners is the number of past matches a and b played together.
+func (ph *PairingHistory) Partners(a, b Person) int {
+	if ph == nil {
+		return 0
+	}
+	return ph.partners[personPair(a, b)]
+}
+
+// Opponents is the number of past matches a and b played against each other.
+func (ph *PairingHistory) Opponents(a, b Person) int {
+	if ph == nil {
+		return 0
+	}
+	return ph.opponents[personPair(a, b)]
+}
+
+// How many times the people of the two teams faced each other.
+func (ph *PairingHistory) teamOpponents(teamA, teamB Team) int {
+	res := 0
+	for _, a := range teamPeople(teamA) {
+		for _, b := range teamPeople(teamB) {
+			res += ph.Opponents(a, b)
+		}
+	}
+	return res
+}
+
+func matchingPenalty(m matching, penalty func(a, b int) float64) float64 {
+	res := 0.0
+	for e := range m {
+		res += penalty(int(e.P1), int(e.P2))
+	}
+	return res
+}
+
+// Like makeMatching, where the edges are soft constraints: the nodes are
+// tried in different orders, and the matching whose edges have the lowest
+// penalty is kept. Only nodes with the same label swap places, so that the
+// order given by the labels is kept; nil labels let every node move. The
+// given order is tried first.
+func makeMatchingAvoiding(
+	nodes []int,
+	k int,
+	labels []int,
+	penalty func(a, b int) float64,
+	random *rand.Rand,
+) matching {
+
+	best := makeMatching(nodes, k)
+	bestPenalty := matchingPenalty(best, penalty)
+
+	positions := make(map[int][]int)
+	for i := range nodes {
+		label := 0
+		if labels != nil {
+			label = labels[i]
+		}
+		positions[label] = append(positions[label], i)
+	}
+
+	order := slices.Clone(nodes)
+	for attempt := 0; attempt < pastPairingAttempts && bestPenalty > 0; attempt++ {
+		for _, label := range slices.Sorted(maps.Keys(positions)) {
+			pos := positions[label]
+			random.Shuffle(len(pos), func(i, j int) {
+				order[pos[i]], order[pos[j]] = order[pos[j]], order[pos[i]]
+			})
+		}
+
+		m := makeMatching(order, k)
+		if p := matchingPenalty(m, penalty); p < bestPenalty {
+			best, bestPenalty = m, p
+		}
+	}
+
+	return best
+}
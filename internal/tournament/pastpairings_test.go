@@ -0,0 +1,72 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAvoidPastPairings(t *testing.T) {
+
+	t.Run("Assertion_1_RodeoAvoidsPastOpponents", func(t *testing.T) {
+		// Last week every team faced the next one.
+		teams := makeTeams(6)
+		history := NewPairingHistory()
+		for i := range teams {
+			history.AddMatch(teams[i], teams[(i+1)%len(teams)])
+		}
+
+		rodeoFactory := RodeoFactory{MaxRounds: 2, AvailableCourts: 3, History: history}
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		for _, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				if history.teamOpponents(*m.TeamA, *m.TeamB) > 0 {
+					t.Errorf("%v and %v already faced each other", *m.TeamA, *m.TeamB)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_SinglePlayerRodeoAvoidsPastPartners", func(t *testing.T) {
+		people := make(map[string]Person)
+		var order []Person
+		for i := range 8 {
+			p := Person{Id: fmt.Sprintf("P%d", i)}
+			people[p.Id] = p
+			order = append(order, p)
+		}
+		history := NewPairingHistory()
+		for i := 0; i < len(order); i += 4 {
+			history.AddMatch(MakeTeam(order[i], order[i+1], Male), MakeTeam(order[i+2], order[i+3], Male))
+		}
+
+		factory := SinglePlayerRodeoFactory{MaxRounds: 2, AvailableCourts: 2, People: people, History: history}
+		for _, team := range factory.generateTeams(2) {
+			if history.Partners(team.Person1, team.Person2) > 0 {
+				t.Errorf("%s and %s were already partners", team.Person1.Id, team.Person2.Id)
+			}
+		}
+	})
+
+	t.Run("Assertion_3_HistoryCounts", func(t *testing.T) {
+		teams := makeTeams(2)
+		history := NewPairingHistory()
+		history.AddMatch(teams[0], teams[1])
+		history.AddMatch(teams[1], teams[0])
+
+		if n := history.Partners(teams[0].Person2, teams[0].Person1); n != 2 {
+			t.Errorf("expected partners twice, got %d", n)
+		}
+		if n := history.teamOpponents(teams[0], teams[1]); n != 8 {
+			t.Errorf("expected 8 opponent pairings, got %d", n)
+		}
+		var empty *PairingHistory
+		if empty.Opponents(teams[0].Person1, teams[1].Person1) != 0 {
+			t.Errorf("expected no pairings in a nil history")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package tournament
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// A past partnership weighs as much as this difference of ratings, when
+// partners are chosen both from the history and from the ratings.
+const pastPartnerPenalty = 10
+
+// Number of pairings of a round tried by balanceOpponents before it keeps the
+// round as it is.
+const balanceSteps = 10_000
+
+func hasRatings(people []Person) bool {
+	return slices.ContainsFunc(people, func(p Person) bool { return p.Rating != 0 })
+}
+
+// The strength of a team is the sum of the ratings of its people.
+func teamStrength(team Team) float64 {
+	return team.Person1.Rating + team.Person2.Rating
+}
+
+// Returns how far the strength of the partners a and b is from the strength
+// of the average team.
+func partnershipImbalance(people []Person) func(a, b int) float64 {
+	mean := 0.0
+	for _, p := range people {
+		mean += p.Rating
+	}
+	mean /= float64(len(people))
+
+	return func(a, b int) float64 {
+		return math.Abs(people[a].Rating + people[b].Rating - 2*mean)
+	}
+}
+
+// Pairs the teams of every round again, so that teams of close strength
+// face each other: teams are sorted by strength and every team faces the
+// strongest team left it can face. Two teams can only face each other when
+// canFace allows it, the constraints are followed and they did not face
+// each other in an earlier round. Matches between teams that must face each
+// other are kept, and so is a round that cannot be paired again. The matches
+// keep their courts.
+func balanceOpponents(rounds []Round, constraints Constraints, canFace func(a, b *Team) bool) {
+	played := make(map[[2][2]string]any)
+	matchKey := func(a, b *Team) [2][2]string {
+		ka, kb := personPair(a.Person1, a.Person2), personPair(b.Person1, b.Person2)
+		if kb[0] < ka[0] || kb[0] == ka[0] && kb[1] < ka[1] {
+			ka, kb = kb, ka
+		}
+		return [2][2]string{ka, kb}
+	}
+
+	for _, round := range rounds {
+		var free []int
+		teams := make([]*Team, 0, 2*len(round.Matches))
+		for i, m := range round.Matches {
+			if constraints.mustFace(*m.TeamA, *m.TeamB) {
+				continue
+			}
+			free = append(free, i)
+			teams = append(teams, m.TeamA, m.TeamB)
+		}
+		slices.SortStableFunc(teams, func(a, b *Team) int {
+			return cmp.Compare(teamStrength(*b), teamStrength(*a))
+		})
+
+		allowed := func(a, b *Team) bool {
+			_, ok := played[matchKey(a, b)]
+			return !ok && canFace(a, b) && constraints.canFace(*a, *b)
+		}
+		paired := make([]bool, len(teams))
+		pairs := make([][2]*Team, 0, len(free))
+		steps := 0
+		var pair func() bool
+		pair = func() bool {
+			first := slices.Index(paired, false)
+			if first == -1 {
+				return true
+			}
+			paired[first] = true
+			for j := first + 1; j < len(teams) && steps < balanceSteps; j++ {
+				steps++
+				if paired[j] || !allowed(teams[first], teams[j]) {
+					continue
+				}
+				paired[j] = true
+				pairs = append(pairs, [2]*Team{teams[first], teams[j]})
+				if pair() {
+					return true
+				}
+				pairs = pairs[:len(pairs)-1]
+				paired[j] = false
+			}
+			paired[first] = false
+			return false
+		}
+
+		if pair() {
+			for i, m := range free {
+				round.Matches[m].TeamA, round.Matches[m].TeamB = pairs[i][0], pairs[i][1]
+			}
+		}
+		for _, m := range round.Matches {
+			played[matchKey(m.TeamA, m.TeamB)] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tournament
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSkillBalancedSinglePlayerRodeo(t *testing.T) {
+	// Ratings go from 1 to 8, the average team is rated 9.
+	people := make(map[string]Person)
+	for i := range 8 {
+		people[fmt.Sprintf("P%d", i)] = Person{Id: fmt.Sprintf("P%d", i), Rating: float64(i + 1)}
+	}
+	factory := SinglePlayerRodeoFactory{MaxRounds: 2, AvailableCourts: 2, People: people}
+
+	// With people resting in every round, a single run is enough to
+	// schedule them.
+	morePeople := make(map[string]Person)
+	for i := range 12 {
+		morePeople[fmt.Sprintf("P%d", i)] = Person{Id: fmt.Sprintf("P%d", i), Rating: float64(i + 1)}
+	}
+	scheduled := SinglePlayerRodeoFactory{MaxRounds: 5, AvailableCourts: 2, People: morePeople}
+
+	t.Run("Assertion_1_BalancedPartners", func(t *testing.T) {
+		for _, team := range factory.generateTeams(2) {
+			if s := teamStrength(team); s < 7 || s > 11 {
+				t.Errorf("%s and %s are rated %v together, expected between 7 and 11",
+					team.Person1.Id, team.Person2.Id, s)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_BalancedOpponents", func(t *testing.T) {
+		rodeo, err := scheduled.MakeTournament(context.Background(), "rodeo", time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		// No team of the round is rated between two opponents.
+		for i, round := range rodeo.Rounds {
+			for _, m := range round.Matches {
+				low := math.Min(teamStrength(*m.TeamA), teamStrength(*m.TeamB))
+				high := math.Max(teamStrength(*m.TeamA), teamStrength(*m.TeamB))
+				for _, other := range round.Matches {
+					for _, team := range []*Team{other.TeamA, other.TeamB} {
+						if s := teamStrength(*team); s > low && s < high {
+							t.Errorf("Round %d: teams rated %v and %v face each other, %v is in between",
+								i+1, high, low, s)
+						}
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_BalanceOpponentsKeepsCourts", func(t *testing.T) {
+		teams := []Team{
+			MakeTeam(Person{Id: "A", Rating: 5}, Person{Id: "B", Rating: 5}, Male),
+			MakeTeam(Person{Id: "C", Rating: 1}, Person{Id: "D", Rating: 1}, Male),
+			MakeTeam(Person{Id: "E", Rating: 5}, Person{Id: "F", Rating: 4}, Male),
+			MakeTeam(Person{Id: "G", Rating: 2}, Person{Id: "H", Rating: 1}, Male),
+		}
+		rounds := []Round{{Matches: []Match{
+			{TeamA: &teams[0], TeamB: &teams[1], CourtId: 3},
+			{TeamA: &teams[2], TeamB: &teams[3], CourtId: 7},
+		}}}
+		balanceOpponents(rounds, Constraints{}, func(a, b *Team) bool { return true })
+
+		m := rounds[0].Matches
+		if *m[0].TeamA != teams[0] || *m[0].TeamB != teams[2] || m[0].CourtId != 3 {
+			t.Errorf("expected the two strongest teams on court 3, got %v", m[0])
+		}
+		if *m[1].TeamA != teams[3] || *m[1].TeamB != teams[1] || m[1].CourtId != 7 {
+			t.Errorf("expected the two weakest teams on court 7, got %v", m[1])
+		}
+	})
+
+	t.Run("Assertion_4_BalanceOpponentsFollowsConstraints", func(t *testing.T) {
+		teams := []Team{
+			MakeTeam(Person{Id: "A", Rating: 5}, Person{Id: "B", Rating: 5}, Male),
+			MakeTeam(Person{Id: "C", Rating: 1}, Person{Id: "D", Rating: 1}, Male),
+			MakeTeam(Person{Id: "E", Rating: 5}, Person{Id: "F", Rating: 4}, Male),
+			MakeTeam(Person{Id: "G", Rating: 2}, Person{Id: "H", Rating: 1}, Male),
+			MakeTeam(Person{Id: "I", Rating: 3}, Person{Id: "J", Rating: 3}, Male),
+			MakeTeam(Person{Id: "K", Rating: 3}, Person{Id: "L", Rating: 2}, Male),
+		}
+		makeRound := func() Round {
+			return Round{Matches: []Match{
+				{TeamA: &teams[0], TeamB: &teams[3], CourtId: 1},
+				{TeamA: &teams[2], TeamB: &teams[4], CourtId: 2},
+				{TeamA: &teams[5], TeamB: &teams[1], CourtId: 3},
+			}}
+		}
+		// A never faces E, and C must face K.
+		constraints := Constraints{
+			NeverFace: []PersonPair{{Person{Id: "A"}, Person{Id: "E"}}},
+			MustFace:  []PersonPair{{Person{Id: "C"}, Person{Id: "K"}}},
+		}
+		expectMatches := func(round Round, expected [][2]int) {
+			t.Helper()
+			for i, m := range round.Matches {
+				if *m.TeamA != teams[expected[i][0]] || *m.TeamB != teams[expected[i][1]] {
+					t.Errorf("expected %s and %s to face each other on court %d, got %s and %s",
+						teams[expected[i][0]].Person1.Id, teams[expected[i][1]].Person1.Id,
+						m.CourtId, m.TeamA.Person1.Id, m.TeamB.Person1.Id)
+				}
+			}
+		}
+
+		// In the second round the teams do not face each other again.
+		rounds := []Round{makeRound(), makeRound()}
+		balanceOpponents(rounds, constraints, func(a, b *Team) bool { return true })
+		expectMatches(rounds[0], [][2]int{{0, 4}, {2, 3}, {5, 1}})
+		expectMatches(rounds[1], [][2]int{{0, 3}, {2, 4}, {5, 1}})
+
+		// The graph has no match between A and I.
+		rounds = []Round{makeRound()}
+		balanceOpponents(rounds, constraints, func(a, b *Team) bool {
+			return personPair(a.Person1, b.Person1) != personPair(Person{Id: "A"}, Person{Id: "I"})
+		})
+		expectMatches(rounds[0], [][2]int{{0, 3}, {2, 4}, {5, 1}})
+	})
+
+	t.Run("Assertion_5_RatingsAndConstraints", func(t *testing.T) {
+		// The two weakest people must face each other, the two strongest
+		// never do.
+		factory := scheduled
+		factory.Constraints = Constraints{
+			NeverFace: []PersonPair{{Person{Id: "P10"}, Person{Id: "P11"}}},
+			MustFace:  []PersonPair{{Person{Id: "P0"}, Person{Id: "P1"}}},
+		}
+		rodeo, err := factory.MakeTournament(context.Background(), "rodeo", time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		if err := factory.Constraints.validateRounds(rodeo.Rounds); err != nil {
+			t.Error(err)
+		}
+	})
+}
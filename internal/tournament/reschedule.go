@@ -0,0 +1,284 @@
+package tournament
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+)
+
+// Number of orderings of the teams tried for every number of matches per
+// team, looking for matches that were not played before the reschedule.
+const rescheduleAttempts = 100
+
+// Reschedule regenerates the rounds of a rodeo from round fromRound on,
+// numbered from 1, for teams, e.g. after a team withdrew or joined late. The
+// rounds before fromRound are kept as they are, and the regenerated rounds
+// keep their start times. No match of the kept rounds is played again, no
+// team faces a team it must never face, and every team plays the same number
+// of matches in the regenerated rounds, or at most one match less when some
+// people are only present in some rounds. The courts, the availability, the
+// constraints and the past pairings of the factory are followed as when the
+// rodeo was made; rf should be built from the Options of the rodeo.
+func (rf *RodeoFactory) Reschedule(
+	ctx context.Context,
+	rodeo *Rodeo,
+	fromRound int,
+	teams []Team,
+) (*Rodeo, error) {
+
+	if fromRound < 1 || fromRound > len(rodeo.Rounds) {
+		return nil, fmt.Errorf(
+			"round %d is not a round of the tournament, rounds go from 1 to %d",
+			fromRound,
+			len(rodeo.Rounds),
+		)
+	}
+	frozen := rodeo.Rounds[:fromRound-1]
+	for i, round := range rodeo.Rounds[fromRound-1:] {
+		for _, m := range round.Matches {
+			if m.MatchStatus == MatchCompleted {
+				return nil, fmt.Errorf(
+					"round %d already has results, reschedule from a later round",
+					fromRound+i,
+				)
+			}
+		}
+	}
+
+	// The teams of the stored rounds are known only by the Ids of their
+	// people.
+	index := make(map[TeamKey]int)
+	for i, team := range teams {
+		if _, ok := index[team.Key()]; ok {
+			return nil, fmt.Errorf("team %v is listed more than once", team)
+		}
+		index[team.Key()] = i
+	}
+	played := make(matching)
+	for _, round := range frozen {
+		for _, m := range round.Matches {
+			a, okA := index[m.TeamA.Key()]
+			b, okB := index[m.TeamB.Key()]
+			if okA && okB {
+				played[edge{P1: Node(min(a, b)), P2: Node(max(a, b))}] = struct{}{}
+			}
+		}
+	}
+
+	// Teams that must never face each other are treated as if they already
+	// played.
+	for i := range teams {
+		for j := i + 1; j < len(teams); j++ {
+			if !rf.Constraints.canFace(teams[i], teams[j]) {
+				played[edge{P1: Node(i), P2: Node(j)}] = struct{}{}
+			}
+		}
+	}
+
+	n := len(teams)
+	roundsNumber := len(rodeo.Rounds) - len(frozen)
+	constraints := rf.Constraints.among(teams)
+
+	// Rounds are numbered as in the whole rodeo, the courts and the
+	// availability are only looked up for the regenerated rounds.
+	capacities := make([]int, roundsNumber)
+	var courtsPerRound [][]int
+	if len(rf.Courts) > 0 {
+		allCourts, err := getCourtsPerRound(
+			rf.Courts,
+			len(rodeo.Rounds),
+			rodeo.DateStart,
+			rf.MatchDuration,
+			rf.Changeover,
+		)
+		if err != nil {
+			return nil, err
+		}
+		courtsPerRound = allCourts[len(frozen):]
+		for i, courts := range courtsPerRound {
+			capacities[i] = min(len(courts), n/2)
+		}
+	} else {
+		for i := range capacities {
+			capacities[i] = min(rf.AvailableCourts, n/2)
+		}
+	}
+
+	var rounds matchings
+	var err error
+	if len(rf.Availability) > 0 {
+		rounds, err = rf.rescheduleWithAvailability(ctx, teams, len(frozen), capacities, played)
+	} else {
+		rounds, err = rf.rescheduleRounds(ctx, teams, fromRound, capacities, played, constraints)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	turns := append([]Round{}, frozen...)
+	for i, round := range makeRodeoRounds(rounds, teams, courtsPerRound) {
+		round.Date = rodeo.Rounds[len(frozen)+i].Date
+		turns = append(turns, round)
+	}
+	if err := constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, len(frozen), rf.CourtWeights)
+
+	res := NewRodeo(rodeo.Name, rodeo.DateStart, teams, turns)
+	res.Options = rf.options()
+	return res, nil
+}
+
+// Regenerates the rounds, with up to capacities[r] matches in round r, where
+// every team plays the same number of matches and no match of played is
+// played again. With a History, teams that faced each other in the past are
+// paired as little as possible.
+func (rf *RodeoFactory) rescheduleRounds(
+	ctx context.Context,
+	teams []Team,
+	fromRound int,
+	capacities []int,
+	played matching,
+	constraints Constraints,
+) (matchings, error) {
+
+	n := len(teams)
+	roundsNumber := len(capacities)
+	random := rand.New(rand.NewPCG(uint64(n), uint64(fromRound)))
+
+	// Teams that must face each other and did not yet, face each other in
+	// the regenerated rounds.
+	var required []edge
+	for _, e := range constraints.requiredEdges(teams) {
+		if _, ok := played[e]; !ok {
+			required = append(required, e)
+		}
+	}
+	penalty := func(a, b int) float64 {
+		if rf.History == nil {
+			return 0
+		}
+		return float64(rf.History.teamOpponents(teams[a], teams[b]))
+	}
+
+	for matchesPerTeam := min(roundsNumber, n-1); matchesPerTeam > 0; matchesPerTeam-- {
+		totalMatches := n * matchesPerTeam / 2
+		if (n*matchesPerTeam)%2 != 0 {
+			continue
+		}
+		matchesPerRound := (totalMatches + roundsNumber - 1) / roundsNumber
+		roundSizes := make([]int, roundsNumber)
+		capacity := 0
+		for i := range roundSizes {
+			roundSizes[i] = min(capacities[i], matchesPerRound)
+			capacity += roundSizes[i]
+		}
+		if capacity < totalMatches {
+			continue
+		}
+
+		for range rescheduleAttempts {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			m := makeMatchingAvoiding(random.Perm(n), matchesPerTeam, nil, penalty, random)
+			m, err := rewireMatching(
+				m,
+				func(a, b int) bool {
+					_, ok := played[edge{P1: Node(min(a, b)), P2: Node(max(a, b))}]
+					return ok
+				},
+				required,
+				random,
+			)
+			if err != nil {
+				continue
+			}
+			graph := MakeGraph()
+			for e := range m {
+				graph.AddEdge(e)
+			}
+
+			rounds, err := rf.makeMatchingsBacktrackingBySize(ctx, graph, roundSizes)
+			if err != nil {
+				continue
+			}
+			if rf.BalanceRests {
+				rounds = balanceRests(ctx, rounds, n, roundSizes)
+			}
+
+			err = validateTournamentRounds(
+				rounds,
+				teams,
+				roundsNumber,
+				roundSizes,
+				uniformMatches(n, matchesPerTeam),
+				uniformMatches(n, matchesPerTeam),
+				nil,
+			)
+			if err != nil {
+				log.Printf("Validation error: %v", err)
+				return nil, err
+			}
+			return rounds, nil
+		}
+	}
+
+	return nil, errors.New("could not reschedule the remaining rounds with the given teams")
+}
+
+// Like rescheduleRounds, when some people are only present in some rounds.
+// The availability windows are numbered as the rounds of the whole rodeo,
+// of which the first frozen are kept.
+func (rf *RodeoFactory) rescheduleWithAvailability(
+	ctx context.Context,
+	teams []Team,
+	frozen int,
+	capacities []int,
+	played matching,
+) (matchings, error) {
+
+	roundsNumber := frozen + len(capacities)
+	if err := validateAvailability(rf.Availability, roundsNumber); err != nil {
+		return nil, err
+	}
+
+	groups := make([][]Person, len(teams))
+	for i, team := range teams {
+		groups[i] = []Person{team.Person1, team.Person2}
+	}
+	present := getPresence(rf.Availability, groups, roundsNumber)
+	for i := range present {
+		present[i] = present[i][frozen:]
+	}
+
+	rounds, planner, err := planWithAvailability(
+		ctx,
+		present,
+		min(len(capacities), len(teams)-1),
+		rf.buildWithAvailability(teams, capacities, played),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	minMatches, maxMatches := planner.bounds()
+	err = validateTournamentRounds(
+		rounds,
+		teams,
+		len(capacities),
+		capacities,
+		minMatches,
+		maxMatches,
+		present,
+	)
+	if err != nil {
+		log.Printf("Validation error: %v", err)
+		return nil, err
+	}
+	return rounds, nil
+}
@@ -0,0 +1,200 @@
+package tournament
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReschedule(t *testing.T) {
+	teams := makeTeams(9)
+	dateStart := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+	rodeoFactory := RodeoFactory{
+		MaxRounds:       7,
+		AvailableCourts: 4,
+		MatchDuration:   20 * time.Minute,
+	}
+	rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams[:8], dateStart)
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+	for r := range 3 {
+		for i := range rodeo.Rounds[r].Matches {
+			rodeo.Rounds[r].Matches[i].MatchStatus = MatchCompleted
+		}
+	}
+
+	// Team00 withdraws after round 3, Team08 joins.
+	newTeams := append([]Team{}, teams[1:]...)
+	rescheduled, err := rodeoFactory.Reschedule(context.Background(), rodeo, 4, newTeams)
+	if err != nil {
+		t.Fatalf("reschedule returned an error: %v", err)
+	}
+
+	t.Run("Assertion_1_FirstRoundsKept", func(t *testing.T) {
+		if len(rescheduled.Rounds) != 7 {
+			t.Fatalf("expected 7 rounds, got %d", len(rescheduled.Rounds))
+		}
+		for r := range 3 {
+			for i, m := range rescheduled.Rounds[r].Matches {
+				original := rodeo.Rounds[r].Matches[i]
+				if *m.TeamA != *original.TeamA || *m.TeamB != *original.TeamB {
+					t.Errorf("Round %d: match %d changed", r+1, i+1)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_RemainingRoundsWithNewTeams", func(t *testing.T) {
+		met := make(map[[2]Team]bool)
+		played := make(map[Team]int)
+		for r, round := range rescheduled.Rounds {
+			for _, m := range round.Matches {
+				if met[[2]Team{*m.TeamA, *m.TeamB}] || met[[2]Team{*m.TeamB, *m.TeamA}] {
+					t.Errorf("Round %d: %v and %v meet again", r+1, *m.TeamA, *m.TeamB)
+				}
+				met[[2]Team{*m.TeamA, *m.TeamB}] = true
+				if r < 3 {
+					continue
+				}
+				if *m.TeamA == teams[0] || *m.TeamB == teams[0] {
+					t.Errorf("Round %d: withdrawn team plays", r+1)
+				}
+				played[*m.TeamA]++
+				played[*m.TeamB]++
+			}
+			if r >= 3 && round.Date != rodeo.Rounds[r].Date {
+				t.Errorf("Round %d: expected start time %v, got %v", r+1, rodeo.Rounds[r].Date, round.Date)
+			}
+		}
+		for _, team := range newTeams {
+			if played[team] != played[newTeams[0]] || played[team] == 0 {
+				t.Errorf("expected every team to play the same number of remaining matches, got %v", played)
+				break
+			}
+		}
+	})
+
+	t.Run("Assertion_3_RoundWithResults", func(t *testing.T) {
+		if _, err := rodeoFactory.Reschedule(context.Background(), rodeo, 3, newTeams); err == nil {
+			t.Errorf("expected an error when rescheduling a round with results")
+		}
+	})
+
+	t.Run("Assertion_4_StoredTeamsWithoutRatings", func(t *testing.T) {
+		// The stored rounds only know the Ids of the people, the new teams
+		// come with ratings.
+		ratedTeams := make([]Team, len(newTeams))
+		for i, team := range newTeams {
+			team.Person1.Rating, team.Person2.Rating = float64(i+1), float64(i+1)
+			ratedTeams[i] = team
+		}
+		res, err := rodeoFactory.Reschedule(context.Background(), rodeo, 4, ratedTeams)
+		if err != nil {
+			t.Fatalf("reschedule returned an error: %v", err)
+		}
+		met := make(map[[2]TeamKey]bool)
+		for r, round := range res.Rounds {
+			for _, m := range round.Matches {
+				a, b := m.TeamA.Key(), m.TeamB.Key()
+				if met[[2]TeamKey{a, b}] || met[[2]TeamKey{b, a}] {
+					t.Errorf("Round %d: %v and %v meet again", r+1, a, b)
+				}
+				met[[2]TeamKey{a, b}] = true
+			}
+		}
+	})
+}
+
+func TestRescheduleWithOptions(t *testing.T) {
+	teams := makeTeams(8)
+	dateStart := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 1, 1, hour, minute, 0, 0, time.UTC)
+	}
+
+	t.Run("Assertion_1_OptionsKept", func(t *testing.T) {
+		rodeoFactory := RodeoFactory{
+			MaxRounds:       5,
+			AvailableCourts: 2,
+			MatchDuration:   20 * time.Minute,
+			Changeover:      5 * time.Minute,
+			BalanceRests:    true,
+			History:         NewPairingHistory(),
+		}
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		expected := RodeoOptions{
+			AvailableCourts:   2,
+			MatchDuration:     20 * time.Minute,
+			Changeover:        5 * time.Minute,
+			BalanceRests:      true,
+			AvoidPastPairings: true,
+		}
+		if !reflect.DeepEqual(rodeo.Options, expected) {
+			t.Errorf("expected options %+v, got %+v", expected, rodeo.Options)
+		}
+		if rf := NewRodeoFactoryWithOptions(5, rodeo.Options, nil); rf.History != nil {
+			t.Errorf("expected no history when none is given")
+		}
+	})
+
+	t.Run("Assertion_2_CourtsFollowed", func(t *testing.T) {
+		// Rounds start at 18:00, 18:25, 18:50, 19:15 and 19:40; court 4 is
+		// closed from round 4 on.
+		courts := []Court{{Id: 1}, {Id: 4, Until: at(19, 10)}, {Id: 7}}
+		rodeoFactory := RodeoFactory{
+			MaxRounds:     5,
+			MatchDuration: 20 * time.Minute,
+			Changeover:    5 * time.Minute,
+			Courts:        courts,
+		}
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+
+		rf := NewRodeoFactoryWithOptions(len(rodeo.Rounds), rodeo.Options, nil)
+		res, err := rf.Reschedule(context.Background(), rodeo, 3, teams[1:])
+		if err != nil {
+			t.Fatalf("reschedule returned an error: %v", err)
+		}
+		for r, round := range res.Rounds[3:] {
+			for _, m := range round.Matches {
+				if m.CourtId != 1 && m.CourtId != 7 {
+					t.Errorf("Round %d: match on court %d, that is closed", r+4, m.CourtId)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_AvailabilityFollowed", func(t *testing.T) {
+		// Team01 leaves after round 3.
+		availability := []Availability{{Person: teams[1].Person1, LastRound: 3}}
+		rodeoFactory := RodeoFactory{
+			MaxRounds:       5,
+			AvailableCourts: 4,
+			Availability:    availability,
+		}
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+
+		rf := NewRodeoFactoryWithOptions(len(rodeo.Rounds), rodeo.Options, nil)
+		res, err := rf.Reschedule(context.Background(), rodeo, 2, teams[1:])
+		if err != nil {
+			t.Fatalf("reschedule returned an error: %v", err)
+		}
+		for r, round := range res.Rounds[3:] {
+			for _, m := range round.Matches {
+				if *m.TeamA == teams[1] || *m.TeamB == teams[1] {
+					t.Errorf("Round %d: %v plays, but has left", r+4, teams[1])
+				}
+			}
+		}
+	})
+}
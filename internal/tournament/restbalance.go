@@ -0,0 +1,140 @@
+package tournament
+
+import (
+	"context"
+	"math/rand/v2"
+)
+
+// Number of changes tried on a schedule while looking for better rests.
+const restBalanceSteps = 20_000
+
+// Resting twice in a row weighs this much more than resting two rounds apart.
+const consecutiveRestWeight = 100
+
+// Scores how badly the rests are spread: every team resting in two
+// consecutive rounds costs consecutiveRestWeight, every team resting two
+// rounds apart costs 1. A schedule where rests are spread evenly costs 0.
+func restCost(rounds matchings, teamsNumber int) int {
+	resting := make([][]bool, len(rounds))
+	for i, round := range rounds {
+		resting[i] = make([]bool, teamsNumber)
+		for team := range teamsNumber {
+			resting[i][team] = true
+		}
+		for e := range round {
+			resting[i][e.P1], resting[i][e.P2] = false, false
+		}
+	}
+
+	cost := 0
+	for i := range resting {
+		for team := range teamsNumber {
+			if !resting[i][team] {
+				continue
+			}
+			if i+1 < len(resting) && resting[i+1][team] {
+				cost += consecutiveRestWeight
+			}
+			if i+2 < len(resting) && resting[i+2][team] {
+				cost++
+			}
+		}
+	}
+	return cost
+}
+
+// Improves a feasible schedule so that no team rests twice in a row, unless
+// it cannot be avoided, and rests are spread evenly. Rounds are swapped and
+// matches are moved or exchanged between rounds, as long as no team plays
+// twice in a round, no round exceeds maxSizes and no round is left empty.
+// Changes are kept when they do not make the rests worse, until the rests
+// cannot get better, the steps run out or ctx is done.
+func balanceRests(ctx context.Context, rounds matchings, teamsNumber int, maxSizes []int) matchings {
+	if len(rounds) < 2 {
+		return rounds
+	}
+
+	res := copyMatchings(rounds)
+	random := rand.New(rand.NewPCG(uint64(teamsNumber), uint64(len(rounds))))
+	cost := restCost(res, teamsNumber)
+
+	for step := 0; step < restBalanceSteps && cost > 0; step++ {
+		if step%100 == 0 && ctx.Err() != nil {
+			break
+		}
+
+		i, j := random.IntN(len(res)), random.IntN(len(res))
+		if i == j || len(res[i]) == 0 {
+			continue
+		}
+
+		var undo func()
+		switch random.IntN(3) {
+		case 0:
+			if len(res[i]) > maxSizes[j] || len(res[j]) > maxSizes[i] {
+				continue
+			}
+			res[i], res[j] = res[j], res[i]
+			undo = func() { res[i], res[j] = res[j], res[i] }
+		case 1:
+			e := randomEdge(res[i], random)
+			if len(res[i]) < 2 || len(res[j]) >= maxSizes[j] || !canJoin(res[j], e, nil) {
+				continue
+			}
+			moveEdge(res[i], res[j], e)
+			undo = func() { moveEdge(res[j], res[i], e) }
+		default:
+			if len(res[j]) == 0 {
+				continue
+			}
+			e1, e2 := randomEdge(res[i], random), randomEdge(res[j], random)
+			if !canJoin(res[j], e1, &e2) || !canJoin(res[i], e2, &e1) {
+				continue
+			}
+			moveEdge(res[i], res[j], e1)
+			moveEdge(res[j], res[i], e2)
+			undo = func() {
+				moveEdge(res[j], res[i], e1)
+				moveEdge(res[i], res[j], e2)
+			}
+		}
+
+		if newCost := restCost(res, teamsNumber); newCost <= cost {
+			cost = newCost
+		} else {
+			undo()
+		}
+	}
+
+	return res
+}
+
+func randomEdge(m matching, random *rand.Rand) edge {
+	k := random.IntN(len(m))
+	for e := range m {
+		if k == 0 {
+			return e
+		}
+		k--
+	}
+	return edge{}
+}
+
+// Tells whether e can be added to the matching without a team playing twice,
+// ignoring the match leaving, when not nil.
+func canJoin(m matching, e edge, leaving *edge) bool {
+	for other := range m {
+		if leaving != nil && other == *leaving {
+			continue
+		}
+		if other.P1 == e.P1 || other.P1 == e.P2 || other.P2 == e.P1 || other.P2 == e.P2 {
+			return false
+		}
+	}
+	return true
+}
+
+func moveEdge(from, to matching, e edge) {
+	delete(from, e)
+	to[e] = struct{}{}
+}
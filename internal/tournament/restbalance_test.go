@@ -0,0 +1,64 @@
+package tournament
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// Returns, for every team, the rounds in which it rests.
+func restingRounds(rounds []Round, teams []Team) map[Team][]int {
+	res := make(map[Team][]int)
+	for i, round := range rounds {
+		playing := make(map[Team]bool)
+		for _, m := range round.Matches {
+			playing[*m.TeamA], playing[*m.TeamB] = true, true
+		}
+		for _, team := range teams {
+			if !playing[team] {
+				res[team] = append(res[team], i)
+			}
+		}
+	}
+	return res
+}
+
+func TestBalanceRests(t *testing.T) {
+	// Every team plays 4 of the 6 rounds, 3 teams rest in every round.
+	teams := makeTeams(9)
+	rodeoFactory := RodeoFactory{
+		MaxRounds:       6,
+		AvailableCourts: 3,
+		BalanceRests:    true,
+	}
+	rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, time.Now())
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+
+	t.Run("Assertion_1_NoConsecutiveRests", func(t *testing.T) {
+		for team, rests := range restingRounds(rodeo.Rounds, teams) {
+			if len(rests) != 2 {
+				t.Errorf("expected %v to rest twice, got rounds %v", team, rests)
+			}
+			for i := 1; i < len(rests); i++ {
+				if rests[i] == rests[i-1]+1 {
+					t.Errorf("%v rests in rounds %d and %d", team, rests[i-1]+1, rests[i]+1)
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_2_RestCost", func(t *testing.T) {
+		// Team 2 rests in rounds 1 and 2, team 0 in rounds 2 and 3, team 1
+		// in rounds 1 and 3.
+		rounds := matchings{
+			{{P1: 0, P2: 3}: struct{}{}},
+			{{P1: 1, P2: 3}: struct{}{}},
+			{{P1: 2, P2: 3}: struct{}{}},
+		}
+		if cost := restCost(rounds, 4); cost != 2*consecutiveRestWeight+1 {
+			t.Errorf("expected cost %d, got %d", 2*consecutiveRestWeight+1, cost)
+		}
+	})
+}
@@ -10,6 +10,8 @@ type Rodeo struct {
 	DateStart time.Time
 	Teams     []Team
 	Rounds    []Round
+	// Options are the options the rounds were generated with.
+	Options RodeoOptions
 }
 
 func (rodeo *Rodeo) GetName() string {
@@ -56,22 +58,19 @@ func (rodeo Rodeo) GetResting(round int, separator string) []string {
 		return []string{}
 	}
 
-	teams := make(map[Team]any)
+	teams := make(map[TeamKey]Team)
 
 	for _, t := range rodeo.Teams {
-		teams[t] = struct{}{}
+		teams[t.Key()] = t
 	}
 
 	for _, m := range rodeo.Rounds[round].Matches {
-		team1 := *m.TeamA
-		team2 := *m.TeamB
-
-		delete(teams, team1)
-		delete(teams, team2)
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
 	}
 
 	res := make([]string, 0)
-	for t := range teams {
+	for _, t := range teams {
 		res = append(
 			res,
 			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
@@ -73,4 +73,19 @@ func TestGetResting(t *testing.T) {
 			t.Errorf("Expected %v, got %v", expected, result)
 		}
 	})
+
+	t.Run("Stored teams without ratings", func(t *testing.T) {
+		// Teams read back from the database only know the Ids of their people.
+		ratedA := Team{Person1: Person{Id: "P1", Rating: 3}, Person2: Person{Id: "P2", Rating: 2}}
+		ratedRodeo := Rodeo{
+			Teams:  []Team{ratedA, teamB, teamC},
+			Rounds: rodeo.Rounds,
+		}
+
+		expected := []string{"P5 - P6"}
+		result := ratedRodeo.GetResting(0, "-")
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got %v", expected, result)
+		}
+	})
 }
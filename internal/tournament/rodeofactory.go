@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"math"
+	"math/rand/v2"
+	"slices"
 	"time"
 )
 
@@ -13,6 +15,89 @@ type RodeoFactory struct {
 	Name            string
 	MaxRounds       int
 	AvailableCourts int
+	// MatchDuration and Changeover give every round its start time. Rounds
+	// have no start time when MatchDuration is 0.
+	MatchDuration time.Duration
+	Changeover    time.Duration
+	// Courts, when given, replace AvailableCourts: every round only uses the
+	// courts that are free for the whole round, and matches are assigned to
+	// the real court ids. It needs MatchDuration.
+	Courts []Court
+	// Availability, when given, limits the rounds each person can play in: a
+	// team only plays in the rounds where both its people are present.
+	Availability []Availability
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+	// BalanceRests makes the solver look, among the feasible schedules, for
+	// one where no team rests twice in a row, when it can be avoided, and
+	// rests are spread evenly.
+	BalanceRests bool
+	// History, when given, holds the pairings of past tournaments: teams
+	// whose people already faced each other are paired as little as
+	// possible.
+	History *PairingHistory
+	// Constraints are the people that must never or must face each other.
+	Constraints Constraints
+}
+
+// RodeoOptions are the options of a RodeoFactory that are kept with the
+// rodeo, so that its rounds can later be regenerated in the same way. The
+// past pairings are not kept, only whether they were avoided.
+type RodeoOptions struct {
+	AvailableCourts   int            `json:"availableCourts"`
+	MatchDuration     time.Duration  `json:"matchDuration"`
+	Changeover        time.Duration  `json:"changeover"`
+	Courts            []Court        `json:"courts,omitempty"`
+	Availability      []Availability `json:"availability,omitempty"`
+	CourtWeights      CourtWeights   `json:"courtWeights,omitempty"`
+	BalanceRests      bool           `json:"balanceRests"`
+	AvoidPastPairings bool           `json:"avoidPastPairings"`
+	Constraints       Constraints    `json:"constraints"`
+}
+
+// NewRodeoFactoryWithOptions returns the factory of a rodeo generated with
+// options. history holds the past pairings, when they are avoided.
+func NewRodeoFactoryWithOptions(turns int, options RodeoOptions, history *PairingHistory) *RodeoFactory {
+	rf := &RodeoFactory{
+		MaxRounds:       turns,
+		AvailableCourts: options.AvailableCourts,
+		MatchDuration:   options.MatchDuration,
+		Changeover:      options.Changeover,
+		Courts:          options.Courts,
+		Availability:    options.Availability,
+		CourtWeights:    options.CourtWeights,
+		BalanceRests:    options.BalanceRests,
+		Constraints:     options.Constraints,
+	}
+	if options.AvoidPastPairings {
+		rf.History = history
+	}
+	return rf
+}
+
+func (rf *RodeoFactory) options() RodeoOptions {
+	return RodeoOptions{
+		AvailableCourts:   rf.AvailableCourts,
+		MatchDuration:     rf.MatchDuration,
+		Changeover:        rf.Changeover,
+		Courts:            rf.Courts,
+		Availability:      rf.Availability,
+		CourtWeights:      rf.CourtWeights,
+		BalanceRests:      rf.BalanceRests,
+		AvoidPastPairings: rf.History != nil,
+		Constraints:       rf.Constraints,
+	}
+}
+
+// Sets the start time of every round from dateStart: a round starts once the
+// previous round has been played and the courts have been changed over.
+func scheduleRoundTimes(rounds []Round, dateStart time.Time, matchDuration, changeover time.Duration) {
+	if matchDuration <= 0 {
+		return
+	}
+	for i := range rounds {
+		rounds[i].Date = dateStart.Add(time.Duration(i) * (matchDuration + changeover))
+	}
 }
 
 func (rf *RodeoFactory) GetFirstValidTournament(
@@ -55,6 +140,29 @@ func (rf *RodeoFactory) MakeTournament(
 	name string,
 	teams []Team,
 	dateStart time.Time) (*Rodeo, error) {
+	rodeo, err := rf.makeTournament(ctx, name, teams, dateStart)
+	if err != nil {
+		return nil, err
+	}
+	rodeo.Options = rf.options()
+	return rodeo, nil
+}
+
+func (rf *RodeoFactory) makeTournament(
+	ctx context.Context,
+	name string,
+	teams []Team,
+	dateStart time.Time) (*Rodeo, error) {
+	if err := rf.Constraints.validate(teams); err != nil {
+		return nil, err
+	}
+	if len(rf.Availability) > 0 {
+		return rf.makeTournamentWithAvailability(ctx, name, teams, dateStart)
+	}
+	if len(rf.Courts) > 0 {
+		return rf.makeTournamentOnCourts(ctx, name, teams, dateStart)
+	}
+
 	n := len(teams)
 	nodes := make([]int, n)
 	for i := range n {
@@ -64,77 +172,287 @@ func (rf *RodeoFactory) MakeTournament(
 	totalMatches, matchesPerTurn, matchesPerTeam :=
 		getMatchesPerTeam(n, rf.MaxRounds, rf.AvailableCourts)
 
-	roundsNumber := rf.MaxRounds
+	roundsNumber := getRoundsNumber(rf.MaxRounds, totalMatches, matchesPerTurn)
+
+	if totalMatches == 0 {
+		return nil, errors.New(
+			"could not determine valid match parameters. Returning empty tournament",
+		)
+	}
+
+	graph, teams, err := rf.getGraph(teams, matchesPerTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	rounds, err := rf.makeMatchings(ctx, graph, len(teams), uniformRoundSizes(matchesPerTurn, roundsNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if rf.BalanceRests {
+		rounds = balanceRests(ctx, rounds, len(teams), uniformRoundSizes(matchesPerTurn, roundsNumber))
+	}
+
+	err = validateTournamentRounds(
+		rounds,
+		teams,
+		roundsNumber,
+		uniformRoundSizes(matchesPerTurn, roundsNumber),
+		uniformMatches(len(teams), matchesPerTeam),
+		uniformMatches(len(teams), matchesPerTeam),
+		nil,
+	)
+	if err != nil {
+		log.Printf("Validation error: %v", err)
+		return nil, err
+	}
+
+	turns := makeRodeoRounds(dropEmptyMatchings(rounds), teams, nil)
+	if err := rf.Constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, 0, rf.CourtWeights)
+	scheduleRoundTimes(turns, dateStart, rf.MatchDuration, rf.Changeover)
+
+	return NewRodeo(name, dateStart, teams, turns), nil
+}
+
+// Schedules the rodeo on the courts that are free in every round. Rounds
+// with fewer free courts host fewer matches, and a round without free courts
+// has no matches.
+func (rf *RodeoFactory) makeTournamentOnCourts(
+	ctx context.Context,
+	name string,
+	teams []Team,
+	dateStart time.Time) (*Rodeo, error) {
+
+	courtsPerRound, err := getCourtsPerRound(
+		rf.Courts,
+		rf.MaxRounds,
+		dateStart,
+		rf.MatchDuration,
+		rf.Changeover,
+	)
+	if err != nil {
+		return nil, err
+	}
 
-	if math.Ceil(matchesPerTurn)*float64(rf.MaxRounds)-1 > float64(totalMatches) {
-		roundsNumber = rf.MaxRounds - 1
+	capacities := make([]int, len(courtsPerRound))
+	for i, courts := range courtsPerRound {
+		capacities[i] = min(len(courts), len(teams)/2)
 	}
 
+	totalMatches, matchesPerTeam := getMatchesPerTeamByRound(len(teams), capacities)
 	if totalMatches == 0 {
 		return nil, errors.New(
-			"could not determine valid match parameters. Returning empty tournament",
+			"could not determine valid match parameters with the available courts",
 		)
 	}
+	roundSizes := balanceRoundSizes(capacities, totalMatches)
 
-	graph, teams := rf.getGraph(teams, matchesPerTeam)
+	graph, teams, err := rf.getGraph(teams, matchesPerTeam)
+	if err != nil {
+		return nil, err
+	}
 
-	var rounds matchings
-	var err error
-	rounds = rf.makeMatchingsHeuristic(graph.GetCopy(), matchesPerTurn, roundsNumber)
+	rounds, err := rf.makeMatchings(ctx, graph, len(teams), roundSizes)
+	if err != nil {
+		return nil, err
+	}
+	if rf.BalanceRests {
+		rounds = balanceRests(ctx, rounds, len(teams), roundSizes)
+	}
 
-	noRoundsAreEmpty := true
-	for _, round := range rounds {
-		if len(round) == 0 {
-			noRoundsAreEmpty = false
-			break
-		}
+	err = validateTournamentRounds(
+		rounds,
+		teams,
+		rf.MaxRounds,
+		roundSizes,
+		uniformMatches(len(teams), matchesPerTeam),
+		uniformMatches(len(teams), matchesPerTeam),
+		nil,
+	)
+	if err != nil {
+		log.Printf("Validation error: %v", err)
+		return nil, err
+	}
+
+	turns := makeRodeoRounds(rounds, teams, courtsPerRound)
+	if err := rf.Constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, 0, rf.CourtWeights)
+	scheduleRoundTimes(turns, dateStart, rf.MatchDuration, rf.Changeover)
+
+	return NewRodeo(name, dateStart, teams, turns), nil
+}
+
+// Schedules the rodeo when some people are only present in some rounds.
+// Rounds are filled one at a time, giving the free courts to the teams that
+// have the most matches left to play in the fewest rounds, so that every team
+// ends up at most one match short of the others, or of one match per round in
+// which it is present.
+func (rf *RodeoFactory) makeTournamentWithAvailability(
+	ctx context.Context,
+	name string,
+	teams []Team,
+	dateStart time.Time) (*Rodeo, error) {
+
+	if err := validateAvailability(rf.Availability, rf.MaxRounds); err != nil {
+		return nil, err
 	}
 
-	if !noRoundsAreEmpty || len(rounds) != roundsNumber {
-		rounds, err = rf.makeMatchingsBacktracking(ctx, graph, matchesPerTurn, roundsNumber)
+	capacities := make([]int, rf.MaxRounds)
+	var courtsPerRound [][]int
+	if len(rf.Courts) > 0 {
+		var err error
+		courtsPerRound, err = getCourtsPerRound(
+			rf.Courts,
+			rf.MaxRounds,
+			dateStart,
+			rf.MatchDuration,
+			rf.Changeover,
+		)
 		if err != nil {
 			return nil, err
 		}
+		for i, courts := range courtsPerRound {
+			capacities[i] = len(courts)
+		}
+	} else {
+		for i := range capacities {
+			capacities[i] = rf.AvailableCourts
+		}
+	}
+
+	groups := make([][]Person, len(teams))
+	for i, team := range teams {
+		groups[i] = []Person{team.Person1, team.Person2}
 	}
+	present := getPresence(rf.Availability, groups, rf.MaxRounds)
+
+	build := rf.buildWithAvailability(teams, capacities, nil)
 
+	rounds, planner, err := planWithAvailability(ctx, present, min(rf.MaxRounds, len(teams)-1), build)
+	if err != nil {
+		return nil, err
+	}
+
+	minMatches, maxMatches := planner.bounds()
 	err = validateTournamentRounds(
 		rounds,
 		teams,
-		roundsNumber,
-		matchesPerTurn,
-		totalMatches,
-		matchesPerTeam,
+		rf.MaxRounds,
+		capacities,
+		minMatches,
+		maxMatches,
+		present,
 	)
 	if err != nil {
 		log.Printf("Validation error: %v", err)
 		return nil, err
 	}
 
+	turns := makeRodeoRounds(rounds, teams, courtsPerRound)
+	if err := rf.Constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, 0, rf.CourtWeights)
+	scheduleRoundTimes(turns, dateStart, rf.MatchDuration, rf.Changeover)
+
+	return NewRodeo(name, dateStart, teams, turns), nil
+}
+
+// Returns the attempts of planWithAvailability, that fill the rounds one at a
+// time with up to capacities[r] matches in round r, giving the free courts
+// to the teams that have the most matches left to play in the fewest rounds.
+// The matches in alreadyPlayed are never played.
+func (rf *RodeoFactory) buildWithAvailability(
+	teams []Team,
+	capacities []int,
+	alreadyPlayed matching,
+) func(*availabilityPlanner) matchings {
+	return func(ap *availabilityPlanner) matchings {
+		rounds := make(matchings, len(capacities))
+		played := make(matching)
+		for e := range alreadyPlayed {
+			played[e] = struct{}{}
+		}
+		for r := range rounds {
+			rounds[r] = make(matching)
+			busy := make(nodeSet)
+			order := ap.order(r)
+			for i, a := range order {
+				if len(rounds[r]) == capacities[r] {
+					break
+				}
+				if busy.contains(a) {
+					continue
+				}
+				// Among the possible opponents, the ones a must face come first.
+				opponent := -1
+				for _, b := range order[i+1:] {
+					e := edge{P1: Node(min(a, b)), P2: Node(max(a, b))}
+					if _, ok := played[e]; ok || busy.contains(b) ||
+						!rf.Constraints.canFace(teams[a], teams[b]) {
+						continue
+					}
+					if opponent < 0 || rf.Constraints.mustFace(teams[a], teams[b]) &&
+						!rf.Constraints.mustFace(teams[a], teams[opponent]) {
+						opponent = b
+					}
+				}
+				if b := opponent; b >= 0 {
+					e := edge{P1: Node(min(a, b)), P2: Node(max(a, b))}
+					rounds[r][e] = struct{}{}
+					played[e] = struct{}{}
+					busy[a], busy[b] = struct{}{}, struct{}{}
+					ap.played[a]++
+					ap.played[b]++
+				}
+			}
+		}
+		return rounds
+	}
+}
+
+// Turns the matchings into rounds. The matches of a round are assigned to
+// the court ids of courtsPerRound, or to courts numbered from 1 when it is
+// nil.
+func makeRodeoRounds(rounds matchings, teams []Team, courtsPerRound [][]int) []Round {
 	var turns []Round
-	for _, matching := range rounds {
+	for i, matching := range rounds {
 		var matches []Match
 
-		currCourt := 1
+		currCourt := 0
 		for edge := range matching {
 			e1 := edge.P1
 			e2 := edge.P2
 
+			courtId := currCourt + 1
+			if courtsPerRound != nil {
+				courtId = courtsPerRound[i][currCourt]
+			}
+
 			m := Match{
 				TeamA:   &teams[e1],
 				TeamB:   &teams[e2],
-				CourtId: currCourt,
+				CourtId: courtId,
 			}
 			currCourt += 1
 			matches = append(matches, m)
 		}
 
-		turns = append(turns, Round{matches})
+		turns = append(turns, Round{Matches: matches})
 	}
-
-	return NewRodeo(name, dateStart, teams, turns), nil
+	return turns
 }
 
-func (rf *RodeoFactory) getGraph(teams []Team, matchesPerTeam int) (Graph, []Team) {
+// Returns the graph of the matches, where every team plays matchesPerTeam
+// matches, and the teams in the order of the nodes. Teams of the same gender
+// are preferably paired together; the constraints are always followed.
+func (rf *RodeoFactory) getGraph(teams []Team, matchesPerTeam int) (Graph, []Team, error) {
 
 	graph := MakeGraph()
 	allMatches := make(matching)
@@ -155,7 +473,7 @@ func (rf *RodeoFactory) getGraph(teams []Team, matchesPerTeam int) (Graph, []Tea
 		}
 
 		for _, v := range nodesByGender {
-			for edge := range makeMatching(v, matchesPerTeam) {
+			for edge := range rf.makeMatching(v, matchesPerTeam, nil, teamsSeparatedByGender) {
 				allMatches[edge] = struct{}{}
 			}
 		}
@@ -166,14 +484,45 @@ func (rf *RodeoFactory) getGraph(teams []Team, matchesPerTeam int) (Graph, []Tea
 		for i := range len(teams) {
 			nodes[i] = i
 		}
-		allMatches = makeMatching(nodes, matchesPerTeam)
 		teamsOrdered = orderTeamsByGender(teams)
+		genders := make([]int, len(teamsOrdered))
+		for i, team := range teamsOrdered {
+			genders[i] = int(team.TeamGender)
+		}
+		allMatches = rf.makeMatching(nodes, matchesPerTeam, genders, teamsOrdered)
+	}
+
+	allMatches, err := rewireMatching(
+		allMatches,
+		func(a, b int) bool { return !rf.Constraints.canFace(teamsOrdered[a], teamsOrdered[b]) },
+		rf.Constraints.requiredEdges(teamsOrdered),
+		rand.New(rand.NewPCG(uint64(len(teams)), uint64(matchesPerTeam))),
+	)
+	if err != nil {
+		return Graph{}, nil, err
 	}
 
 	for edge := range allMatches {
 		graph.AddEdge(edge)
 	}
-	return graph, teamsOrdered
+	return graph, teamsOrdered, nil
+}
+
+// Like makeMatching, where the nodes are the given teams. With a History,
+// teams that faced each other in the past are paired as little as possible;
+// only teams with the same label swap places.
+func (rf *RodeoFactory) makeMatching(nodes []int, k int, labels []int, teams []Team) matching {
+	if rf.History == nil {
+		return makeMatching(nodes, k)
+	}
+
+	return makeMatchingAvoiding(
+		nodes,
+		k,
+		labels,
+		func(a, b int) float64 { return float64(rf.History.teamOpponents(teams[a], teams[b])) },
+		rand.New(rand.NewPCG(uint64(len(nodes)), uint64(k))),
+	)
 }
 
 func canAllGendersPlayOnlyAgainstEachOther(teams []Team, matchesPerTeam int) bool {
@@ -199,6 +548,9 @@ func canAllGendersPlayOnlyAgainstEachOther(teams []Team, matchesPerTeam int) boo
 
 func getMatchesPerTeam(teamsNumber int, totalRounds int, availableCourts int) (int, float64, int) {
 
+	// A team plays at most once per round, so no round has more than
+	// teamsNumber/2 matches, whatever the courts.
+	availableCourts = min(availableCourts, teamsNumber/2)
 	matchesPerTeam := totalRounds
 
 	for matchesPerTeam > 0 {
@@ -221,64 +573,76 @@ func getMatchesPerTeam(teamsNumber int, totalRounds int, availableCourts int) (i
 	return 0, 0.0, 0
 }
 
-func (rf *RodeoFactory) makeMatchingsHeuristic(
-	graph Graph,
-	avgMatchingSize float64,
-	totalMatchings int,
-) matchings {
-	var res matchings
-	maxMatchesPerTurn := int(math.Ceil(avgMatchingSize))
-
-	playingTeams := make(nodeSet)
-	turnMatches := make(matching)
-
-	for len(res) < totalMatchings {
-		addedSomething := false
-
-		for i := range graph.nodes {
-			neighbors := graph.GetNeighbors(i)
-
-			var player2 Node
-			foundPlayer2 := false
-
-			for _, neighbor := range neighbors {
-				if _, playing := playingTeams[int(neighbor)]; !playing {
-					player2 = neighbor
-					foundPlayer2 = true
-					break
-				}
-			}
+// Returns the rounds that are played: the last round is dropped when the
+// other rounds, full, hold all the matches but one. It is kept when the other
+// rounds cannot hold all the matches, e.g. 7 matches, at most 3 per round,
+// need all of 3 rounds.
+func getRoundsNumber(totalRounds, totalMatches int, matchesPerTurn float64) int {
+	maxPerRound := math.Ceil(matchesPerTurn)
+	if maxPerRound*float64(totalRounds)-1 > float64(totalMatches) &&
+		maxPerRound*float64(totalRounds-1) >= float64(totalMatches) {
+		return totalRounds - 1
+	}
+	return totalRounds
+}
 
-			if !playingTeams.contains(int(i)) && foundPlayer2 {
-				addedSomething = true
-				player1 := i
+// Removes the rounds without matches, that are left when there are fewer
+// matches than rounds.
+func dropEmptyMatchings(rounds matchings) matchings {
+	return slices.DeleteFunc(rounds, func(m matching) bool { return len(m) == 0 })
+}
 
-				playingTeams[int(player1)] = struct{}{}
-				playingTeams[int(player2)] = struct{}{}
+// Like getMatchesPerTeam, with a different number of matches available in
+// every round. A team plays at most once per round, so only the rounds with
+// at least one match count.
+func getMatchesPerTeamByRound(teamsNumber int, roundSizes []int) (int, int) {
+	capacity, playableRounds := 0, 0
+	for _, size := range roundSizes {
+		capacity += size
+		if size > 0 {
+			playableRounds++
+		}
+	}
 
-				turnMatches[edge{player1, player2}] = struct{}{}
+	for matchesPerTeam := playableRounds; matchesPerTeam > 0; matchesPerTeam-- {
+		totalParticipations := teamsNumber * matchesPerTeam
+		if totalParticipations%2 == 0 && totalParticipations/2 <= capacity &&
+			teamsNumber > matchesPerTeam {
+			return totalParticipations / 2, matchesPerTeam
+		}
+	}
 
-				graph.RemoveEdge(edge{player1, Node(player2)})
+	return 0, 0
+}
 
-			}
+// Reduces the round sizes until they add up to totalMatches, always taking a
+// match from the largest round so that matches are spread evenly.
+func balanceRoundSizes(roundSizes []int, totalMatches int) []int {
+	res := make([]int, len(roundSizes))
+	copy(res, roundSizes)
 
-			isLastTurnRequirement := len(res) == totalMatchings-1 && graph.Empty()
+	capacity := 0
+	for _, size := range res {
+		capacity += size
+	}
 
-			if len(turnMatches) == maxMatchesPerTurn || isLastTurnRequirement {
-				if len(turnMatches) > 0 {
-					res = append(res, turnMatches)
-					playingTeams = make(nodeSet)
-					turnMatches = make(matching)
-				}
+	for ; capacity > totalMatches; capacity-- {
+		largest := 0
+		for i, size := range res {
+			if size >= res[largest] {
+				largest = i
 			}
-
-		}
-
-		if !addedSomething {
-			break
 		}
+		res[largest]--
 	}
+	return res
+}
 
+func uniformRoundSizes(avgMatchingSize float64, totalMatchings int) []int {
+	res := make([]int, totalMatchings)
+	for i := range res {
+		res[i] = int(math.Ceil(avgMatchingSize))
+	}
 	return res
 }
 
@@ -339,14 +703,46 @@ func orderTeamsByGender(teams []Team) []Team {
 	return orderedTeams
 }
 
+// Splits the edges of the graph into matchings, the matching i with at most
+// maxSizes[i] edges. The edges are coloured in polynomial time, and the
+// backtracking search is only used when the colours cannot be spread over the
+// rounds.
+func (rf *RodeoFactory) makeMatchings(
+	ctx context.Context,
+	graph Graph,
+	nodes int,
+	maxSizes []int,
+) (matchings, error) {
+	rounds, err := makeMatchingsColouring(graph, nodes, maxSizes)
+	if err == nil {
+		return rounds, nil
+	}
+	log.Printf("falling back to backtracking: %v", err)
+	return rf.makeMatchingsBacktrackingBySize(ctx, graph, maxSizes)
+}
+
 func (rf *RodeoFactory) makeMatchingsBacktracking(
 	ctx context.Context,
 	initialEdges Graph,
 	avgMatchingSize float64,
 	totalMatchings int,
 ) (matchings, error) {
+	return rf.makeMatchingsBacktrackingBySize(
+		ctx,
+		initialEdges,
+		uniformRoundSizes(avgMatchingSize, totalMatchings),
+	)
+}
+
+// Like makeMatchingsBacktracking, the matching i has at most maxSizes[i]
+// edges.
+func (rf *RodeoFactory) makeMatchingsBacktrackingBySize(
+	ctx context.Context,
+	initialEdges Graph,
+	maxSizes []int,
+) (matchings, error) {
 
-	maxMatchingSize := int(math.Ceil(avgMatchingSize))
+	totalMatchings := len(maxSizes)
 
 	var edgeList []edge
 	for e := range initialEdges.GetEdgesIterator() {
@@ -375,14 +771,14 @@ func (rf *RodeoFactory) makeMatchingsBacktracking(
 		0,
 		buckets,
 		usedNodes,
-		maxMatchingSize,
+		maxSizes,
 	)
 
 	if success {
 		return result, nil
 	}
 
-	return nil, errors.New("could not find valid matchings with the given parameters")
+	return nil, errNoMatchings
 }
 
 func (rf *RodeoFactory) solveRecursive(
@@ -392,7 +788,7 @@ func (rf *RodeoFactory) solveRecursive(
 	edgeIdx int,
 	buckets matchings,
 	usedNodes []nodeSet,
-	maxSize int) (matchings, bool) {
+	maxSizes []int) (matchings, bool) {
 
 	select {
 	case <-ctx.Done():
@@ -414,7 +810,7 @@ func (rf *RodeoFactory) solveRecursive(
 		options := 0
 		for i := range buckets {
 			if !usedNodes[i].contains(int(e.P1)) && !usedNodes[i].contains(int(e.P2)) &&
-				len(buckets[i]) < maxSize {
+				len(buckets[i]) < maxSizes[i] {
 				options++
 			}
 		}
@@ -442,7 +838,7 @@ func (rf *RodeoFactory) solveRecursive(
 
 		if !nodesInBucket.contains(p1) &&
 			!nodesInBucket.contains(p2) &&
-			len(bucketEdges) < maxSize {
+			len(bucketEdges) < maxSizes[i] {
 
 			bucketEdges[currentEdge] = struct{}{}
 			nodesInBucket[p1] = struct{}{}
@@ -456,7 +852,7 @@ func (rf *RodeoFactory) solveRecursive(
 				edgeIdx+1,
 				buckets,
 				usedNodes,
-				maxSize,
+				maxSizes,
 			)
 			if found {
 				return sol, true
@@ -472,32 +868,32 @@ func (rf *RodeoFactory) solveRecursive(
 	return nil, false
 }
 
+// Checks that the rounds form a valid rodeo: no round is larger than
+// allowed, no match is played twice, no team plays twice in a round and team
+// i plays between minMatches[i] and maxMatches[i] matches. When present is
+// not nil, team i only plays in the rounds r where present[i][r] is true.
 func validateTournamentRounds(
 	rounds matchings,
 	teams []Team,
 	totalRounds int,
-	matchesPerTurn float64,
-	totalMatches int,
-	matchesPerTeam int) error {
+	maxMatchesPerRound []int,
+	minMatches, maxMatches []int,
+	present [][]bool) error {
 	if len(rounds) != totalRounds {
 		return fmt.Errorf("expected %d rounds, got %d", totalRounds, len(rounds))
 	}
 
-	maxMatchesPerTurn := int(math.Ceil(matchesPerTurn))
-
 	for i, round := range rounds {
-		if len(round) > maxMatchesPerTurn {
+		if len(round) > maxMatchesPerRound[i] {
 			return fmt.Errorf("round %d violated constraint: expected <= %d matches, got %d",
-				i+1, maxMatchesPerTurn, len(round))
+				i+1, maxMatchesPerRound[i], len(round))
 		}
 	}
 
 	scheduledEdges := make(matching)
-	totalScheduledCount := 0
 
 	for i, round := range rounds {
 		for edge := range round {
-			totalScheduledCount++
 			if _, exists := scheduledEdges[edge]; exists {
 				return fmt.Errorf(
 					"match scheduled twice: teams %v and %v were scheduled again in round %d",
@@ -510,54 +906,61 @@ func validateTournamentRounds(
 		}
 	}
 
-	if totalScheduledCount != totalMatches {
-		return fmt.Errorf(
-			"not every match was scheduled: total scheduled count %d does not match total matches %d",
-			totalScheduledCount,
-			totalMatches,
-		)
-	}
-
 	scheduledNodes := make(map[Node]int)
 	for i, round := range rounds {
 
 		scheduledInRound := make(map[Node]struct{})
 		for edge := range round {
+			for _, node := range []Node{edge.P1, edge.P2} {
+				if _, exists := scheduledInRound[node]; exists {
+					return fmt.Errorf(
+						"team %v scheduled more than once across all rounds (found twice in round %d)",
+						teams[node],
+						i+1,
+					)
+				}
+				if present != nil && !present[node][i] {
+					return fmt.Errorf(
+						"team %v scheduled in round %d, when it is not present",
+						teams[node],
+						i+1,
+					)
+				}
 
-			if _, exists := scheduledInRound[edge.P1]; exists {
-				return fmt.Errorf(
-					"team %v scheduled more than once across all rounds (found twice in round %d)",
-					teams[edge.P1],
-					i+1,
-				)
+				scheduledInRound[node] = struct{}{}
+				scheduledNodes[node] += 1
 			}
+		}
+	}
 
-			if _, exists := scheduledInRound[edge.P2]; exists {
+	for i := range teams {
+		count := scheduledNodes[Node(i)]
+		if count < minMatches[i] || count > maxMatches[i] {
+			if minMatches[i] == maxMatches[i] {
 				return fmt.Errorf(
-					"team %v scheduled more than once across all rounds (found twice in round %d)",
-					teams[edge.P2],
-					i+1,
+					"team %v scheduled %d times, expected %d times",
+					teams[i],
+					count,
+					minMatches[i],
 				)
 			}
-
-			scheduledInRound[edge.P1] = struct{}{}
-			scheduledInRound[edge.P2] = struct{}{}
-			scheduledNodes[edge.P1] += 1
-			scheduledNodes[edge.P2] += 1
-		}
-	}
-
-	for node, count := range scheduledNodes {
-
-		if count != matchesPerTeam {
 			return fmt.Errorf(
-				"team %v scheduled %d times, expected %d times",
-				teams[node],
+				"team %v scheduled %d times, expected between %d and %d times",
+				teams[i],
 				count,
-				matchesPerTeam,
+				minMatches[i],
+				maxMatches[i],
 			)
 		}
 	}
 
 	return nil
 }
+
+func uniformMatches(teamsNumber, matchesPerTeam int) []int {
+	res := make([]int, teamsNumber)
+	for i := range res {
+		res[i] = matchesPerTeam
+	}
+	return res
+}
@@ -2,6 +2,7 @@ package tournament
 
 import (
 	"context"
+	"math"
 	"runtime"
 	"testing"
 	"time"
@@ -482,3 +483,106 @@ func TestMakeTournamentMaximiseMaleMatches(t *testing.T) {
 	})
 
 }
+
+func TestMakeTournamentRoundTimes(t *testing.T) {
+	teams := makeTeams(6)
+	dateStart := time.Date(2026, 1, 1, 18, 0, 0, 0, time.UTC)
+
+	rodeoFactory := RodeoFactory{
+		MaxRounds:       5,
+		AvailableCourts: 3,
+		MatchDuration:   20 * time.Minute,
+		Changeover:      5 * time.Minute,
+	}
+	rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+	if err != nil {
+		t.Fatalf("makeTournament returned an error: %v", err)
+	}
+
+	t.Run("Assertion_1_RoundsStartAfterMatchAndChangeover", func(t *testing.T) {
+		for i, round := range rodeo.GetRounds() {
+			expected := dateStart.Add(time.Duration(i) * 25 * time.Minute)
+			if !round.Date.Equal(expected) {
+				t.Errorf("Round %d: expected start %v, got %v", i+1, expected, round.Date)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_NoDurationNoTimes", func(t *testing.T) {
+		rodeoFactory.MatchDuration = 0
+		rodeo, err := rodeoFactory.MakeTournament(context.Background(), "rodeo", teams, dateStart)
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		for i, round := range rodeo.GetRounds() {
+			if !round.Date.IsZero() {
+				t.Errorf("Round %d: expected no start time, got %v", i+1, round.Date)
+			}
+		}
+	})
+}
+
+func TestGetRoundsNumber(t *testing.T) {
+	t.Run("Assertion_1_LastRoundNeeded", func(t *testing.T) {
+		// 2 full rounds hold 6 of the 7 matches.
+		if n := getRoundsNumber(3, 7, 7.0/3); n != 3 {
+			t.Errorf("expected 3 rounds, got %d", n)
+		}
+	})
+
+	t.Run("Assertion_2_SameRoundsAsBefore", func(t *testing.T) {
+		// Whenever the rounds hold all the matches, as many rounds are
+		// played as when the last round was dropped without checking it.
+		for teams := 2; teams <= 20; teams++ {
+			for rounds := 1; rounds <= 10; rounds++ {
+				for courts := 1; courts <= teams/2; courts++ {
+					totalMatches, matchesPerTurn, _ := getMatchesPerTeam(teams, rounds, courts)
+					maxPerRound := math.Ceil(matchesPerTurn)
+					before := rounds
+					if maxPerRound*float64(rounds)-1 > float64(totalMatches) {
+						before = rounds - 1
+					}
+					if maxPerRound*float64(before) < float64(totalMatches) {
+						continue
+					}
+					if n := getRoundsNumber(rounds, totalMatches, matchesPerTurn); n != before {
+						t.Errorf("%d teams, %d rounds, %d courts: expected %d rounds, got %d",
+							teams, rounds, courts, before, n)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_EveryMatchIsScheduled", func(t *testing.T) {
+		rf := RodeoFactory{MaxRounds: 3, AvailableCourts: 3}
+		rodeo, err := rf.MakeTournament(context.Background(), "rodeo", makeTeams(7), time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		matches := 0
+		for _, round := range rodeo.Rounds {
+			matches += len(round.Matches)
+		}
+		if len(rodeo.Rounds) != 3 || matches != 7 {
+			t.Errorf("expected 7 matches in 3 rounds, got %d in %d", matches, len(rodeo.Rounds))
+		}
+	})
+
+	t.Run("Assertion_4_NoEmptyRounds", func(t *testing.T) {
+		// 4 teams play 6 matches, one per round.
+		rf := RodeoFactory{MaxRounds: 8, AvailableCourts: 2}
+		rodeo, err := rf.MakeTournament(context.Background(), "rodeo", makeTeams(4), time.Now())
+		if err != nil {
+			t.Fatalf("makeTournament returned an error: %v", err)
+		}
+		if len(rodeo.Rounds) != 6 {
+			t.Errorf("expected 6 rounds, got %d", len(rodeo.Rounds))
+		}
+		for i, round := range rodeo.Rounds {
+			if len(round.Matches) == 0 {
+				t.Errorf("round %d has no matches", i+1)
+			}
+		}
+	})
+}
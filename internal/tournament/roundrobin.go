@@ -0,0 +1,86 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RoundRobin is a league where every team meets every other team once, or
+// twice in a double round robin. Each round is a match day with its own date.
+type RoundRobin struct {
+	Name      string
+	DateStart time.Time
+	Teams     []Team
+	Rounds    []Round
+}
+
+func (roundRobin *RoundRobin) GetName() string {
+	return roundRobin.Name
+}
+
+func (roundRobin *RoundRobin) GetDateStart() time.Time {
+	return roundRobin.DateStart
+}
+
+func (roundRobin *RoundRobin) GetTeams() []Team {
+	return roundRobin.Teams
+}
+
+func (roundRobin *RoundRobin) GetRounds() []Round {
+	return roundRobin.Rounds
+}
+
+func NewRoundRobin(name string, dateStart time.Time, teams []Team, rounds []Round) *RoundRobin {
+	return &RoundRobin{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeRoundRobin(name string, dateStart time.Time, teams []Team, rounds []Round) RoundRobin {
+	return RoundRobin{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (roundRobin *RoundRobin) GetTournamentType() TournamentType {
+	return TournamentTypeRoundRobin
+}
+
+func (roundRobin RoundRobin) GetResting(round int, separator string) []string {
+	if round > len(roundRobin.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range roundRobin.Teams {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range roundRobin.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetStandings returns the league table.
+func (roundRobin *RoundRobin) GetStandings() []TeamStanding {
+	return GetTeamStandings(roundRobin.Teams, roundRobin.Rounds)
+}
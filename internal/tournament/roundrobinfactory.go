@@ -0,0 +1,83 @@
+package tournament
+
+import (
+	"errors"
+	"time"
+)
+
+type RoundRobinFactory struct {
+	Name            string
+	AvailableCourts int
+	// Legs is 1 for a single round robin, 2 for a double one where the second
+	// leg repeats the first with TeamA and TeamB swapped.
+	Legs int
+	// DaysBetweenRounds is the number of days between two match days.
+	DaysBetweenRounds int
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewRoundRobinFactory(availableCourts, legs, daysBetweenRounds int) *RoundRobinFactory {
+	return &RoundRobinFactory{
+		AvailableCourts:   availableCourts,
+		Legs:              legs,
+		DaysBetweenRounds: daysBetweenRounds,
+	}
+}
+
+// MakeTournament schedules the whole league with the circle method, the
+// first match day being dateStart. With an odd number of teams one team rests
+// on every match day. A match day with more matches than courts is split in
+// consecutive rounds of the same day.
+func (rf *RoundRobinFactory) MakeTournament(
+	name string,
+	teams []Team,
+	dateStart time.Time,
+) (*RoundRobin, error) {
+
+	if len(teams) < 2 {
+		return nil, errors.New("a round robin needs at least two teams")
+	}
+	if rf.AvailableCourts <= 0 {
+		return nil, errors.New("at least one court is needed to generate a round")
+	}
+	if rf.Legs != 1 && rf.Legs != 2 {
+		return nil, errors.New("a round robin is played over one or two legs")
+	}
+	if rf.DaysBetweenRounds <= 0 {
+		return nil, errors.New("match days must be at least one day apart")
+	}
+
+	roundRobin := NewRoundRobin(name, dateStart, teams, nil)
+	schedule := circleMethod(len(teams))
+
+	matchDay := 0
+	for leg := range rf.Legs {
+		for r, edges := range schedule {
+			matches := make([]Match, 0, len(edges))
+			for i, e := range edges {
+				a, b := &roundRobin.Teams[e.P1], &roundRobin.Teams[e.P2]
+				// Alternates which team is listed first, and swaps them
+				// in the second leg.
+				if (r+i+leg)%2 == 1 {
+					a, b = b, a
+				}
+				matches = append(matches, Match{TeamA: a, TeamB: b})
+			}
+
+			date := dateStart.AddDate(0, 0, matchDay*rf.DaysBetweenRounds)
+			for start := 0; start < len(matches); start += rf.AvailableCourts {
+				chunk := matches[start:min(start+rf.AvailableCourts, len(matches))]
+				for i := range chunk {
+					chunk[i].CourtId = i + 1
+				}
+				roundRobin.Rounds = append(roundRobin.Rounds, Round{Matches: chunk, Date: date})
+			}
+			matchDay++
+		}
+	}
+
+	balanceCourts(roundRobin.Rounds, 0, rf.CourtWeights)
+
+	return roundRobin, nil
+}
@@ -0,0 +1,93 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMakeDoubleRoundRobin(t *testing.T) {
+
+	teams := makeTeams(5)
+	dateStart := time.Date(2025, time.November, 3, 20, 0, 0, 0, time.UTC)
+	factory := RoundRobinFactory{AvailableCourts: 2, Legs: 2, DaysBetweenRounds: 7}
+
+	roundRobin, err := factory.MakeTournament("league", teams, dateStart)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building round robin: %v", err)
+	}
+
+	t.Run("Assertion_1_OneMatchDayPerWeek", func(t *testing.T) {
+		if len(roundRobin.Rounds) != 10 {
+			t.Fatalf("expected 10 match days, got %d", len(roundRobin.Rounds))
+		}
+		for i, round := range roundRobin.Rounds {
+			expected := dateStart.AddDate(0, 0, 7*i)
+			if !round.Date.Equal(expected) {
+				t.Errorf("Round %d: expected date %v, got %v", i+1, expected, round.Date)
+			}
+			if resting := roundRobin.GetResting(i, "-"); len(resting) != 1 {
+				t.Errorf("Round %d: expected one team resting, got %v", i+1, resting)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_EveryPairMeetsOnceAtHome", func(t *testing.T) {
+		played := make(map[[2]Team]int)
+		for _, round := range roundRobin.Rounds {
+			for _, m := range round.Matches {
+				played[[2]Team{*m.TeamA, *m.TeamB}]++
+			}
+		}
+		for i := range teams {
+			for j := range teams {
+				if i != j && played[[2]Team{teams[i], teams[j]}] != 1 {
+					t.Errorf("expected %v to host %v once", teams[i], teams[j])
+				}
+			}
+		}
+	})
+
+	t.Run("Assertion_3_InvalidLegs", func(t *testing.T) {
+		factory := RoundRobinFactory{AvailableCourts: 1, Legs: 3, DaysBetweenRounds: 7}
+		if _, err := factory.MakeTournament("league", teams, dateStart); err == nil {
+			t.Errorf("expected an error with three legs")
+		}
+	})
+}
+
+func TestMakeRoundRobinWithFewerCourtsThanMatches(t *testing.T) {
+
+	teams := makeTeams(6)
+	dateStart := time.Date(2025, time.November, 3, 20, 0, 0, 0, time.UTC)
+	factory := RoundRobinFactory{AvailableCourts: 2, Legs: 1, DaysBetweenRounds: 7}
+
+	roundRobin, err := factory.MakeTournament("league", teams, dateStart)
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building round robin: %v", err)
+	}
+
+	t.Run("Assertion_1_MatchDaysAreSplit", func(t *testing.T) {
+		// 5 match days of 3 matches, each played in two rounds.
+		if len(roundRobin.Rounds) != 10 {
+			t.Fatalf("expected 10 rounds, got %d", len(roundRobin.Rounds))
+		}
+		for i, round := range roundRobin.Rounds {
+			expected := dateStart.AddDate(0, 0, 7*(i/2))
+			if !round.Date.Equal(expected) {
+				t.Errorf("Round %d: expected date %v, got %v", i+1, expected, round.Date)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_NoCourtIsUsedTwice", func(t *testing.T) {
+		for i, round := range roundRobin.Rounds {
+			courts := make(map[int]bool)
+			for _, m := range round.Matches {
+				if courts[m.CourtId] {
+					t.Errorf("court %d is used twice in round %d", m.CourtId, i+1)
+				}
+				courts[m.CourtId] = true
+			}
+		}
+	})
+}
@@ -60,13 +60,14 @@ func (rodeo *SinglePlayerRodeo) GetTournamentType() TournamentType {
 	return TournamentTypeSinglePlayerRodeo
 }
 
-func (rodeo SinglePlayerRodeo) getPeople() map[Person]any {
+// Returns the Ids of the people of the rodeo.
+func (rodeo SinglePlayerRodeo) getPeople() map[string]any {
 
-	people := make(map[Person]any)
+	people := make(map[string]any)
 
 	for _, t := range rodeo.Teams {
-		people[t.Person1] = struct{}{}
-		people[t.Person2] = struct{}{}
+		people[t.Person1.Id] = struct{}{}
+		people[t.Person2.Id] = struct{}{}
 	}
 	return people
 
@@ -80,10 +81,10 @@ func (rodeo SinglePlayerRodeo) GetResting(round int, separator string) []string
 	people := rodeo.getPeople()
 
 	for _, m := range rodeo.Rounds[round].Matches {
-		team1person1 := m.TeamA.Person1
-		team2person1 := m.TeamB.Person1
-		team1person2 := m.TeamA.Person2
-		team2person2 := m.TeamB.Person2
+		team1person1 := m.TeamA.Person1.Id
+		team2person1 := m.TeamB.Person1.Id
+		team1person2 := m.TeamA.Person2.Id
+		team2person2 := m.TeamB.Person2.Id
 
 		delete(people, team1person1)
 		delete(people, team2person1)
@@ -92,10 +93,10 @@ func (rodeo SinglePlayerRodeo) GetResting(round int, separator string) []string
 	}
 
 	res := make([]string, 0)
-	for p := range people {
+	for id := range people {
 		res = append(
 			res,
-			p.Id,
+			id,
 		)
 	}
 
@@ -5,6 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -12,7 +15,27 @@ type SinglePlayerRodeoFactory struct {
 	Name            string
 	MaxRounds       int
 	AvailableCourts int
-	People          map[Person]any
+	// People are the players, by their Id.
+	People map[string]Person
+	// MatchDuration and Changeover give every round its start time, see
+	// RodeoFactory.
+	MatchDuration time.Duration
+	Changeover    time.Duration
+	// Availability, when given, limits the rounds each person can play in.
+	Availability []Availability
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+	// History, when given, holds the pairings of past tournaments: people
+	// that already played together are partnered as little as possible.
+	History *PairingHistory
+	// Pairing tells which people can be partners, by their gender.
+	Pairing PairingMode
+	// FixedPairs are couples that always play together, e.g. spouses, while
+	// everybody else rotates partners. Their people must be in People.
+	FixedPairs []Team
+	// Constraints are the people that must never be partners, and that must
+	// never or must face each other.
+	Constraints Constraints
 }
 
 func (rf *SinglePlayerRodeoFactory) GetFirstValidTournament(
@@ -53,14 +76,29 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 	ctx context.Context,
 	name string,
 	dateStart time.Time) (*SinglePlayerRodeo, error) {
-	n := len(rf.People)
-	nodes := make([]int, n)
-	for i := range n {
-		nodes[i] = i
+	if err := rf.Constraints.validate(rf.FixedPairs); err != nil {
+		return nil, err
+	}
+	if len(rf.Availability) > 0 {
+		return rf.makeTournamentWithAvailability(ctx, name, dateStart)
 	}
 
+	if err := rf.validateFixedPairs(); err != nil {
+		return nil, err
+	}
+	_, rotating := rf.fixedPairs()
+	if err := rf.Pairing.validate(rotating, true); err != nil {
+		return nil, err
+	}
+
+	n := len(rf.People)
 	matchesPerPerson :=
 		getMatchesPerPerson(n, rf.MaxRounds, rf.AvailableCourts)
+	k := matchesPerPerson.MatchesPerPerson
+	for k > 0 && ((n*k)%4 != 0 || !rf.Pairing.canPair(rotating, k)) {
+		k -= 1
+	}
+	matchesPerPerson = makeMatchesPerPerson(n, k, rf.MaxRounds)
 
 	roundsNumber := rf.MaxRounds
 
@@ -73,6 +111,9 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 
 	teams := rf.generateTeams(matchesPerPerson.MatchesPerPerson)
 	graph := rf.getGraph(teams)
+	if err := rf.Constraints.forceMustFace(&graph, teams); err != nil {
+		return nil, err
+	}
 
 	var rounds matchings
 	var err error
@@ -107,6 +148,8 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 	}
 
 	var turns []Round
+	// The node of every team of the rounds, fixed pairs are more than one.
+	nodes := make(map[*Team]Node)
 	for _, matching := range rounds {
 		var matches []Match
 
@@ -117,6 +160,7 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 
 			teamA := teams[e1]
 			teamB := teams[e2]
+			nodes[&teamA], nodes[&teamB] = e1, e2
 
 			m := Match{
 				TeamA:   &teamA,
@@ -127,8 +171,19 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 			matches = append(matches, m)
 		}
 
-		turns = append(turns, Round{matches})
+		turns = append(turns, Round{Matches: matches})
+	}
+	if hasRatings(rf.getPeople()) {
+		// Teams are only paired again to matches of the graph.
+		balanceOpponents(turns, rf.Constraints, func(a, b *Team) bool {
+			return graph.HasEdge(edge{P1: nodes[a], P2: nodes[b]})
+		})
 	}
+	if err := rf.Constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, 0, rf.CourtWeights)
+	scheduleRoundTimes(turns, dateStart, rf.MatchDuration, rf.Changeover)
 
 	singlePlayerRodeo := MakeSinglePlayerRodeo(
 		name,
@@ -140,35 +195,378 @@ func (rf *SinglePlayerRodeoFactory) MakeTournament(
 	return &singlePlayerRodeo, nil
 }
 
-func (rf *SinglePlayerRodeoFactory) generateTeams(matchesPerPerson int) []Team {
-	teams := make([]Team, 0)
+// Schedules the rodeo when some people are only present in some rounds.
+// Like RodeoFactory, rounds are filled one at a time with the people that
+// have the most matches left to play in the fewest rounds. Every match puts
+// together four people, split in two teams of people that were never
+// partners before. The people of a fixed pair are planned together.
+func (rf *SinglePlayerRodeoFactory) makeTournamentWithAvailability(
+	ctx context.Context,
+	name string,
+	dateStart time.Time) (*SinglePlayerRodeo, error) {
 
-	people := make([]Person, 0, len(rf.People))
-	for p := range rf.People {
-		people = append(people, p)
+	if err := validateAvailability(rf.Availability, rf.MaxRounds); err != nil {
+		return nil, err
+	}
+
+	if err := rf.validateFixedPairs(); err != nil {
+		return nil, err
+	}
+	fixed, rotating := rf.fixedPairs()
+	if err := rf.Pairing.validate(rotating, false); err != nil {
+		return nil, err
+	}
+
+	// People are planned in units, a fixed pair or a person that rotates.
+	people := slices.Concat(GetPeople(fixed), rotating)
+	units := make([][]int, 0, len(fixed)+len(rotating))
+	groups := make([][]Person, 0, len(fixed)+len(rotating))
+	for i := range fixed {
+		units = append(units, []int{2 * i, 2*i + 1})
+		groups = append(groups, []Person{people[2*i], people[2*i+1]})
+	}
+	for i := 2 * len(fixed); i < len(people); i++ {
+		units = append(units, []int{i})
+		groups = append(groups, []Person{people[i]})
+	}
+	present := getPresence(rf.Availability, groups, rf.MaxRounds)
+	rules := pairRules{people: people, mode: rf.Pairing, constraints: rf.Constraints}
+
+	build := func(ap *availabilityPlanner) []Round {
+		rounds := make([]Round, rf.MaxRounds)
+		partners := make(map[[2]int]any)
+		for r := range rounds {
+			var group []int
+			size := 0
+			for _, u := range ap.order(r) {
+				if len(rounds[r].Matches) == rf.AvailableCourts {
+					break
+				}
+				if size+len(units[u]) > 4 {
+					continue
+				}
+
+				group = append(group, u)
+				size += len(units[u])
+				if size < 4 {
+					continue
+				}
+
+				pairs, ok := splitUnitsInPairs(units, group, partners, rules)
+				if !ok {
+					size -= len(units[group[len(group)-1]])
+					group = group[:len(group)-1]
+					continue
+				}
+				for _, pair := range pairs {
+					partners[pair] = struct{}{}
+				}
+				for _, q := range group {
+					ap.played[q]++
+				}
+
+				teamA := makePairTeam(people[pairs[0][0]], people[pairs[0][1]])
+				teamB := makePairTeam(people[pairs[1][0]], people[pairs[1][1]])
+				rounds[r].Matches = append(rounds[r].Matches, Match{
+					TeamA:   &teamA,
+					TeamB:   &teamB,
+					CourtId: len(rounds[r].Matches) + 1,
+				})
+				group, size = nil, 0
+			}
+		}
+		return rounds
+	}
+
+	maxMatchesPerPerson := min(rf.MaxRounds, len(people)-1)
+	turns, planner, err := planWithAvailability(ctx, present, maxMatchesPerPerson, build)
+	if err != nil {
+		return nil, err
+	}
+
+	unitMin, unitMax := planner.bounds()
+	minMatches := make([]int, len(people))
+	maxMatches := make([]int, len(people))
+	peoplePresent := make([][]bool, len(people))
+	for u, unit := range units {
+		for _, p := range unit {
+			minMatches[p], maxMatches[p], peoplePresent[p] = unitMin[u], unitMax[u], present[u]
+		}
+	}
+	err = validatePeopleRounds(turns, people, rf.AvailableCourts, minMatches, maxMatches, peoplePresent)
+	if err != nil {
+		return nil, err
+	}
+	if hasRatings(rf.getPeople()) {
+		balanceOpponents(turns, rf.Constraints, func(a, b *Team) bool {
+			return !teamsContainSamePerson(*a, *b)
+		})
+	}
+	if err := rf.Constraints.validateRounds(turns); err != nil {
+		return nil, err
+	}
+	balanceCourts(turns, 0, rf.CourtWeights)
+	scheduleRoundTimes(turns, dateStart, rf.MatchDuration, rf.Changeover)
+
+	var teams []Team
+	for _, round := range turns {
+		for _, m := range round.Matches {
+			teams = append(teams, *m.TeamA, *m.TeamB)
+		}
+	}
+
+	singlePlayerRodeo := MakeSinglePlayerRodeo(
+		name,
+		dateStart,
+		teams,
+		turns,
+	)
+
+	return &singlePlayerRodeo, nil
+}
+
+// Tells which people, by their index in people, can be partners and which
+// pairs can face each other.
+type pairRules struct {
+	people      []Person
+	mode        PairingMode
+	constraints Constraints
+}
+
+func (pr pairRules) canPartner(pair [2]int) bool {
+	a, b := pr.people[pair[0]], pr.people[pair[1]]
+	return pr.mode.allows(a, b) && pr.constraints.canPartner(a, b)
+}
+
+func (pr pairRules) canFace(pairs [2][2]int) bool {
+	return pr.constraints.canFace(
+		MakeTeam(pr.people[pairs[0][0]], pr.people[pairs[0][1]], Else),
+		MakeTeam(pr.people[pairs[1][0]], pr.people[pairs[1][1]], Else),
+	)
+}
+
+// Splits the people of the units in two pairs: fixed pairs stay together, the
+// other people are paired like splitInNewPairs does.
+func splitUnitsInPairs(
+	units [][]int,
+	group []int,
+	partners map[[2]int]any,
+	rules pairRules,
+) ([2][2]int, bool) {
+
+	var fixed [][2]int
+	var singles []int
+	for _, u := range group {
+		if len(units[u]) == 2 {
+			fixed = append(fixed, [2]int{units[u][0], units[u][1]})
+		} else {
+			singles = append(singles, units[u][0])
+		}
+	}
+
+	switch len(fixed) {
+	case 0:
+		return splitInNewPairs(singles, partners, rules)
+	case 1:
+		pair := [2]int{min(singles[0], singles[1]), max(singles[0], singles[1])}
+		pairs := [2][2]int{fixed[0], pair}
+		if _, known := partners[pair]; known || !rules.canPartner(pair) || !rules.canFace(pairs) {
+			return [2][2]int{}, false
+		}
+		return pairs, true
+	default:
+		pairs := [2][2]int{fixed[0], fixed[1]}
+		return pairs, rules.canFace(pairs)
+	}
+}
+
+// Splits the four people in two pairs of people that were never partners and
+// that the rules allow, if possible. Among the possible splits, the one with
+// the closest pair strengths is chosen.
+func splitInNewPairs(
+	group []int,
+	partners map[[2]int]any,
+	rules pairRules,
+) ([2][2]int, bool) {
+	pair := func(a, b int) [2]int { return [2]int{min(a, b), max(a, b)} }
+	strength := func(p [2]int) float64 { return rules.people[p[0]].Rating + rules.people[p[1]].Rating }
+
+	var res [2][2]int
+	found := false
+	for _, other := range []int{1, 2, 3} {
+		var rest []int
+		for i := 1; i < 4; i++ {
+			if i != other {
+				rest = append(rest, group[i])
+			}
+		}
+		pairs := [2][2]int{pair(group[0], group[other]), pair(rest[0], rest[1])}
+		_, known0 := partners[pairs[0]]
+		_, known1 := partners[pairs[1]]
+		if known0 || known1 ||
+			!rules.canPartner(pairs[0]) || !rules.canPartner(pairs[1]) || !rules.canFace(pairs) {
+			continue
+		}
+		if !found || math.Abs(strength(pairs[0])-strength(pairs[1])) <
+			math.Abs(strength(res[0])-strength(res[1])) {
+			res, found = pairs, true
+		}
+	}
+	return res, found
+}
+
+// Checks that no round uses more than availableCourts courts, nobody plays
+// twice in a round or in a round where they are not present, and person i
+// plays between minMatches[i] and maxMatches[i] matches.
+func validatePeopleRounds(
+	rounds []Round,
+	people []Person,
+	availableCourts int,
+	minMatches, maxMatches []int,
+	present [][]bool) error {
+
+	index := make(map[string]int)
+	for i, p := range people {
+		index[p.Id] = i
 	}
 
-	nodes := make([]int, len(people))
-	for i := range people {
-		nodes[i] = i
+	played := make([]int, len(people))
+	for r, round := range rounds {
+		if len(round.Matches) > availableCourts {
+			return fmt.Errorf("round %d violated constraint: expected <= %d matches, got %d",
+				r+1, availableCourts, len(round.Matches))
+		}
+
+		inRound := make(map[string]any)
+		for _, m := range round.Matches {
+			for _, p := range []Person{m.TeamA.Person1, m.TeamA.Person2, m.TeamB.Person1, m.TeamB.Person2} {
+				if _, ok := inRound[p.Id]; ok {
+					return fmt.Errorf("%s scheduled twice in round %d", p.Id, r+1)
+				}
+				if !present[index[p.Id]][r] {
+					return fmt.Errorf("%s scheduled in round %d, when they are not present", p.Id, r+1)
+				}
+				inRound[p.Id] = struct{}{}
+				played[index[p.Id]]++
+			}
+		}
 	}
 
-	m := makeMatching(nodes, matchesPerPerson)
+	for i, p := range people {
+		if played[i] < minMatches[i] || played[i] > maxMatches[i] {
+			return fmt.Errorf(
+				"%s scheduled %d times, expected between %d and %d times",
+				p.Id,
+				played[i],
+				minMatches[i],
+				maxMatches[i],
+			)
+		}
+	}
+	return nil
+}
+
+func (rf *SinglePlayerRodeoFactory) generateTeams(matchesPerPerson int) []Team {
+	teams := make([]Team, 0)
+	fixed, people := rf.fixedPairs()
+
+	var penalty func(a, b int) float64
+	if rf.History != nil || hasRatings(people) {
+		imbalance := partnershipImbalance(people)
+		penalty = func(a, b int) float64 {
+			return pastPartnerPenalty*float64(rf.History.Partners(people[a], people[b])) +
+				imbalance(a, b)
+		}
+	}
+	random := rand.New(rand.NewPCG(uint64(len(people)), uint64(matchesPerPerson)))
+	m := rf.Pairing.makePartners(people, matchesPerPerson, penalty, random)
+	// When the partners cannot follow the constraints, the rounds are
+	// rejected once scheduled.
+	rewired, err := rewireMatching(
+		m,
+		func(a, b int) bool {
+			return !rf.Pairing.allows(people[a], people[b]) ||
+				!rf.Constraints.canPartner(people[a], people[b])
+		},
+		nil,
+		random,
+	)
+	if err == nil {
+		m = rewired
+	}
 	for e := range m {
 		x := e.P1
 		y := e.P2
 
-		team := MakeTeam(people[x], people[y], Male)
+		team := makePairTeam(people[x], people[y])
 		teams = append(teams, team)
 	}
+	// A fixed pair is the same team in every match its people play.
+	for _, team := range fixed {
+		for range matchesPerPerson {
+			teams = append(teams, team)
+		}
+	}
 	return teams
 }
 
+// Checks that the people of the fixed pairs are in People, and that nobody
+// is in two fixed pairs.
+func (rf *SinglePlayerRodeoFactory) validateFixedPairs() error {
+	ids := make(map[string]any)
+	for _, p := range rf.People {
+		ids[p.Id] = struct{}{}
+	}
+
+	paired := make(map[string]any)
+	for _, team := range rf.FixedPairs {
+		for _, p := range []Person{team.Person1, team.Person2} {
+			if _, ok := ids[p.Id]; !ok {
+				return fmt.Errorf("%q is in a fixed pair, but does not play in the tournament", p.Id)
+			}
+			if _, ok := paired[p.Id]; ok {
+				return fmt.Errorf("%s is in more than one fixed pair", p.Id)
+			}
+			paired[p.Id] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// Returns the fixed pairs, made of the people in People, and the people that
+// rotate partners, sorted by Id.
+func (rf *SinglePlayerRodeoFactory) fixedPairs() ([]Team, []Person) {
+	byId := make(map[string]Person)
+	for _, p := range rf.People {
+		byId[p.Id] = p
+	}
+
+	paired := make(map[string]any)
+	fixed := make([]Team, 0, len(rf.FixedPairs))
+	for _, team := range rf.FixedPairs {
+		fixed = append(fixed, makePairTeam(byId[team.Person1.Id], byId[team.Person2.Id]))
+		paired[team.Person1.Id] = struct{}{}
+		paired[team.Person2.Id] = struct{}{}
+	}
+
+	var rotating []Person
+	for _, p := range rf.getPeople() {
+		if _, ok := paired[p.Id]; !ok {
+			rotating = append(rotating, p)
+		}
+	}
+	return fixed, rotating
+}
+
+func makePairTeam(person1, person2 Person) Team {
+	return MakeTeam(person1, person2, PairGender(person1, person2))
+}
+
 func teamsContainSamePerson(l Team, r Team) bool {
-	counter := make(map[Person]any)
+	counter := make(map[string]any)
 
 	for _, p := range []Person{l.Person1, l.Person2, r.Person1, r.Person2} {
-		counter[p] = struct{}{}
+		counter[p.Id] = struct{}{}
 	}
 
 	return len(counter) != 4
@@ -181,7 +579,8 @@ func (rf *SinglePlayerRodeoFactory) getGraph(teams []Team) Graph {
 
 	for i := 0; i < n-1; i += 1 {
 		for j := i + 1; j < n; j += 1 {
-			if !teamsContainSamePerson(teams[i], teams[j]) {
+			if !teamsContainSamePerson(teams[i], teams[j]) &&
+				rf.Constraints.canFace(teams[i], teams[j]) {
 				graph.AddEdge(edge{
 					P1: Node(i),
 					P2: Node(j),
@@ -213,6 +612,11 @@ func getMatchesPerPerson(
 		k -= 1
 	}
 
+	return makeMatchesPerPerson(peopleNumber, k, totalRounds)
+}
+
+// The matches of a rodeo where every person plays k matches.
+func makeMatchesPerPerson(peopleNumber, k, totalRounds int) matchesPerPerson {
 	totalMatches := (k * peopleNumber) / 4
 	matchesPerRound := int(math.Ceil(float64(totalMatches) / float64(totalRounds)))
 
@@ -223,8 +627,17 @@ func getMatchesPerPerson(
 	}
 }
 
+func (rf *SinglePlayerRodeoFactory) getPeople() []Person {
+	people := make([]Person, 0, len(rf.People))
+	for _, p := range rf.People {
+		people = append(people, p)
+	}
+	slices.SortFunc(people, func(a, b Person) int { return strings.Compare(a.Id, b.Id) })
+	return people
+}
+
 func NewSinglePlayerRodeoRodeoFactory(
-	turns int, participants map[Person]any,
+	turns int, participants map[string]Person,
 	availableCourts int,
 ) *SinglePlayerRodeoFactory {
 	return &SinglePlayerRodeoFactory{
@@ -274,7 +687,7 @@ func (rf *SinglePlayerRodeoFactory) makeMatchingsBacktracking(
 		return result, nil
 	}
 
-	return nil, errors.New("could not find valid matchings with the given parameters")
+	return nil, errNoMatchings
 }
 
 func mapIntersect(a map[int]any, b map[int]any) map[int]any {
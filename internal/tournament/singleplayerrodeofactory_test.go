@@ -7,11 +7,11 @@ import (
 	"time"
 )
 
-var fourPeople = map[Person]any{
-	{Id: "Tizio"}:     struct{}{},
-	{Id: "Caio"}:      struct{}{},
-	{Id: "Sempronio"}: struct{}{},
-	{Id: "Fazio"}:     struct{}{},
+var fourPeople = map[string]Person{
+	"Tizio":     {Id: "Tizio"},
+	"Caio":      {Id: "Caio"},
+	"Sempronio": {Id: "Sempronio"},
+	"Fazio":     {Id: "Fazio"},
 }
 
 func TestComputeMatchesPerPerson(t *testing.T) {
@@ -113,10 +113,10 @@ func TestGenerateSinglePlayerRodeo(t *testing.T) {
 
 func TestGenerateSinglePlayerRodeo25People(t *testing.T) {
 
-	people := make(map[Person]any)
+	people := make(map[string]Person)
 
 	for i := range 25 {
-		people[Person{Id: fmt.Sprint(i)}] = struct{}{}
+		people[fmt.Sprint(i)] = Person{Id: fmt.Sprint(i)}
 	}
 
 	singlePlayerRodeoFactory := SinglePlayerRodeoFactory{
@@ -167,3 +167,70 @@ func TestGenerateSinglePlayerRodeo25People(t *testing.T) {
 	})
 
 }
+
+func TestGenerateSinglePlayerRodeoWithFixedPairs(t *testing.T) {
+
+	people := make(map[string]Person)
+	for i := range 8 {
+		people[fmt.Sprint(i)] = Person{Id: fmt.Sprint(i)}
+	}
+	fixedPairs := []Team{
+		MakeTeam(Person{Id: "0"}, Person{Id: "1"}, Male),
+		MakeTeam(Person{Id: "2"}, Person{Id: "3"}, Male),
+	}
+
+	// Tells whether the fixed pairs always play together.
+	checkFixedPairs := func(t *testing.T, rounds []Round) {
+		partner := map[string]string{"0": "1", "1": "0", "2": "3", "3": "2"}
+		matches := 0
+		for i, round := range rounds {
+			for _, m := range round.Matches {
+				matches++
+				for _, team := range []*Team{m.TeamA, m.TeamB} {
+					if p, ok := partner[team.Person1.Id]; ok && team.Person2.Id != p {
+						t.Errorf("Round %d: %s plays with %s", i+1, team.Person1.Id, team.Person2.Id)
+					}
+					if p, ok := partner[team.Person2.Id]; ok && team.Person1.Id != p {
+						t.Errorf("Round %d: %s plays with %s", i+1, team.Person2.Id, team.Person1.Id)
+					}
+				}
+			}
+		}
+		if matches == 0 {
+			t.Errorf("expected some matches")
+		}
+	}
+
+	t.Run("Assertion_1_FixedPairsNeverSplit", func(t *testing.T) {
+		singlePlayerRodeoFactory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          people,
+			FixedPairs:      fixedPairs,
+		}
+		checkFixedPairs(t, makeFirstValidRodeo(t, singlePlayerRodeoFactory).Rounds)
+	})
+
+	t.Run("Assertion_2_FixedPairsWithAvailability", func(t *testing.T) {
+		singlePlayerRodeoFactory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          people,
+			FixedPairs:      fixedPairs,
+			Availability:    []Availability{{Person: Person{Id: "4"}, LastRound: 3}},
+		}
+		checkFixedPairs(t, makeFirstValidRodeo(t, singlePlayerRodeoFactory).Rounds)
+	})
+
+	t.Run("Assertion_3_FixedPairOfUnknownPerson", func(t *testing.T) {
+		singlePlayerRodeoFactory := SinglePlayerRodeoFactory{
+			MaxRounds:       4,
+			AvailableCourts: 2,
+			People:          people,
+			FixedPairs:      []Team{MakeTeam(Person{Id: "0"}, Person{Id: "9"}, Male)},
+		}
+		if err := singlePlayerRodeoFactory.validateFixedPairs(); err == nil {
+			t.Errorf("expected an error for a fixed pair with a person that does not play")
+		}
+	})
+}
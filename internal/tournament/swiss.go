@@ -0,0 +1,87 @@
+package tournament
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Swiss is played one round at a time, each round pairing teams with similar
+// standings that have not met yet. Far fewer rounds than a round robin are
+// enough to rank many teams.
+type Swiss struct {
+	Name      string
+	DateStart time.Time
+	Teams     []Team
+	Rounds    []Round
+}
+
+func (swiss *Swiss) GetName() string {
+	return swiss.Name
+}
+
+func (swiss *Swiss) GetDateStart() time.Time {
+	return swiss.DateStart
+}
+
+func (swiss *Swiss) GetTeams() []Team {
+	return swiss.Teams
+}
+
+func (swiss *Swiss) GetRounds() []Round {
+	return swiss.Rounds
+}
+
+func NewSwiss(name string, dateStart time.Time, teams []Team, rounds []Round) *Swiss {
+	return &Swiss{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func MakeSwiss(name string, dateStart time.Time, teams []Team, rounds []Round) Swiss {
+	return Swiss{
+		Name:      name,
+		DateStart: dateStart,
+		Teams:     teams,
+		Rounds:    rounds,
+	}
+}
+
+func (swiss *Swiss) GetTournamentType() TournamentType {
+	return TournamentTypeSwiss
+}
+
+func (swiss Swiss) GetResting(round int, separator string) []string {
+	if round > len(swiss.Rounds)-1 || round < 0 {
+		return []string{}
+	}
+
+	teams := make(map[TeamKey]Team)
+	for _, t := range swiss.Teams {
+		teams[t.Key()] = t
+	}
+
+	for _, m := range swiss.Rounds[round].Matches {
+		delete(teams, m.TeamA.Key())
+		delete(teams, m.TeamB.Key())
+	}
+
+	res := make([]string, 0)
+	for _, t := range teams {
+		res = append(
+			res,
+			fmt.Sprintf("%s %s %s", t.Person1.Id, separator, t.Person2.Id),
+		)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// GetStandings returns the teams ranked by their results so far.
+func (swiss *Swiss) GetStandings() []TeamStanding {
+	return GetTeamStandings(swiss.Teams, swiss.Rounds)
+}
@@ -0,0 +1,269 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Limits the pairing search, so that an impossible pairing fails in a
+// reasonable time.
+const swissMaxPairingSteps = 1_000_000
+
+// SwissFactory generates the rounds of a Swiss tournament. MaxRounds is not
+// enforced when it is 0.
+type SwissFactory struct {
+	Name            string
+	MaxRounds       int
+	AvailableCourts int
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewSwissFactory(turns, availableCourts int) *SwissFactory {
+	return &SwissFactory{
+		MaxRounds:       turns,
+		AvailableCourts: availableCourts,
+	}
+}
+
+// MakeTournament generates the first round, where teams are sorted by seed:
+// the top half of the teams meets the bottom half.
+func (sf *SwissFactory) MakeTournament(
+	name string,
+	teams []Team,
+	dateStart time.Time,
+) (*Swiss, error) {
+
+	if len(teams) < 2 {
+		return nil, errors.New("a swiss tournament needs at least two teams")
+	}
+
+	swiss := NewSwiss(name, dateStart, teams, nil)
+
+	firstRound, err := sf.makeSwissRound(swiss)
+	if err != nil {
+		return nil, err
+	}
+	swiss.Rounds = []Round{firstRound}
+
+	return swiss, nil
+}
+
+// MakeNextRound pairs the teams according to the standings, once every match
+// of the last round has been completed. A Swiss round with more matches than
+// courts is played over consecutive rounds.
+func (sf *SwissFactory) MakeNextRound(swiss *Swiss) (Round, error) {
+	swissRounds, partial := groupSwissRounds(swiss.Rounds, len(swiss.Teams)/2)
+	if sf.MaxRounds > 0 && len(swissRounds) >= sf.MaxRounds && partial == len(swiss.Rounds) {
+		return Round{}, fmt.Errorf("all the %d rounds have been played", sf.MaxRounds)
+	}
+
+	if len(swiss.Rounds) > 0 {
+		for _, m := range swiss.Rounds[len(swiss.Rounds)-1].Matches {
+			if m.MatchStatus != MatchCompleted {
+				return Round{}, errors.New("the last round is not over: some matches have no result yet")
+			}
+		}
+	}
+
+	return sf.makeSwissRound(swiss)
+}
+
+// Groups the rounds played so far in Swiss rounds of matchesPerRound matches.
+// Returns the complete Swiss rounds, and the index of the first round of the
+// Swiss round that is still being played, or len(rounds).
+func groupSwissRounds(rounds []Round, matchesPerRound int) ([]Round, int) {
+	var res []Round
+	start := 0
+	group := Round{}
+	for i, round := range rounds {
+		group.Matches = append(group.Matches, round.Matches...)
+		if len(group.Matches) >= matchesPerRound {
+			res = append(res, group)
+			group = Round{}
+			start = i + 1
+		}
+	}
+	return res, start
+}
+
+func (sf *SwissFactory) makeSwissRound(swiss *Swiss) (Round, error) {
+	if sf.AvailableCourts <= 0 {
+		return Round{}, errors.New("at least one court is needed to generate a round")
+	}
+
+	index := make(map[Team]int)
+	for i, t := range swiss.Teams {
+		index[t] = i
+	}
+	swissRounds, partial := groupSwissRounds(swiss.Rounds, len(swiss.Teams)/2)
+
+	// The pairs that already met, and the teams that already had a bye.
+	played := MakeGraph()
+	hadBye := make(map[int]bool)
+	for _, round := range swissRounds {
+		playing := make(map[int]bool)
+		for _, m := range round.Matches {
+			a, b := index[*m.TeamA], index[*m.TeamB]
+			played.AddEdge(edge{Node(a), Node(b)})
+			playing[a], playing[b] = true, true
+		}
+		for i := range swiss.Teams {
+			if !playing[i] {
+				hadBye[i] = true
+			}
+		}
+	}
+
+	standings := GetTeamStandings(swiss.Teams, swiss.Rounds[:partial])
+	ranked := make([]int, len(standings))
+	wins := make(map[int]int)
+	for i, s := range standings {
+		ranked[i] = index[s.Team]
+		wins[ranked[i]] = s.Won
+	}
+
+	pairer := swissPairer{played: played, wins: wins}
+
+	var pairs []edge
+	var err error
+	if len(ranked)%2 == 0 {
+		pairs, err = pairer.pair(ranked)
+	} else {
+		pairs, err = pairer.pairWithBye(ranked, hadBye)
+	}
+	if err != nil {
+		return Round{}, err
+	}
+
+	// The pairs of the Swiss round being played that did not play yet.
+	started := MakeGraph()
+	for _, round := range swiss.Rounds[partial:] {
+		for _, m := range round.Matches {
+			started.AddEdge(edge{Node(index[*m.TeamA]), Node(index[*m.TeamB])})
+		}
+	}
+	var matches []Match
+	for _, p := range pairs {
+		if started.HasEdge(p) || len(matches) == sf.AvailableCourts {
+			continue
+		}
+		matches = append(matches, Match{
+			TeamA:   &swiss.Teams[p.P1],
+			TeamB:   &swiss.Teams[p.P2],
+			CourtId: len(matches) + 1,
+		})
+	}
+
+	round := Round{Matches: matches}
+	balanceNextRoundCourts(swiss.Rounds, round, sf.CourtWeights)
+
+	return round, nil
+}
+
+type swissPairer struct {
+	played Graph
+	wins   map[int]int
+	steps  int
+	// Number of pairs that already met allowed in the pairing.
+	rematches int
+}
+
+// The lowest ranked team that did not have a bye yet rests, and the others
+// are paired. When every team already had a bye, any team may rest. Rematches
+// are allowed as a last resort, as few as possible.
+func (sp *swissPairer) pairWithBye(ranked []int, hadBye map[int]bool) ([]edge, error) {
+	for rematches := 0; rematches <= len(ranked)/2; rematches++ {
+		for _, allowRepeated := range []bool{false, true} {
+			for i := len(ranked) - 1; i >= 0; i-- {
+				if hadBye[ranked[i]] && !allowRepeated {
+					continue
+				}
+
+				rest := make([]int, 0, len(ranked)-1)
+				rest = append(rest, ranked[:i]...)
+				rest = append(rest, ranked[i+1:]...)
+
+				if pairs, ok := sp.pairWithRematches(rest, rematches); ok {
+					return pairs, nil
+				}
+			}
+		}
+	}
+	return nil, errors.New("could not pair the teams")
+}
+
+// Pairs the teams, with as few rematches as possible.
+func (sp *swissPairer) pair(ranked []int) ([]edge, error) {
+	for rematches := 0; rematches <= len(ranked)/2; rematches++ {
+		if pairs, ok := sp.pairWithRematches(ranked, rematches); ok {
+			return pairs, nil
+		}
+	}
+	return nil, errors.New("could not pair the teams")
+}
+
+func (sp *swissPairer) pairWithRematches(ranked []int, rematches int) ([]edge, bool) {
+	sp.steps = 0
+	sp.rematches = rematches
+	return sp.solve(ranked, nil)
+}
+
+// Returns the opponents to try for the best ranked team of rest. Teams with
+// the same wins come first, starting from the middle of the group so that its
+// top half meets its bottom half. Then the teams of the lower groups follow:
+// the team floats down.
+func (sp *swissPairer) candidates(rest []int) []int {
+	groupSize := 1
+	for groupSize < len(rest) && sp.wins[rest[groupSize]] == sp.wins[rest[0]] {
+		groupSize++
+	}
+
+	half := max(groupSize/2, 1)
+	res := make([]int, 0, len(rest)-1)
+	res = append(res, rest[half:groupSize]...)
+	res = append(res, rest[1:half]...)
+	res = append(res, rest[groupSize:]...)
+	return res
+}
+
+func (sp *swissPairer) solve(rest []int, pairs []edge) ([]edge, bool) {
+	if len(rest) == 0 {
+		return pairs, true
+	}
+	sp.steps++
+	if sp.steps > swissMaxPairingSteps {
+		return nil, false
+	}
+
+	top := rest[0]
+	for _, opponent := range sp.candidates(rest) {
+		rematch := sp.played.HasEdge(edge{Node(top), Node(opponent)})
+		if rematch && sp.rematches == 0 {
+			continue
+		}
+		if rematch {
+			sp.rematches--
+		}
+
+		next := make([]int, 0, len(rest)-2)
+		for _, t := range rest[1:] {
+			if t != opponent {
+				next = append(next, t)
+			}
+		}
+
+		if res, ok := sp.solve(next, append(pairs, edge{Node(top), Node(opponent)})); ok {
+			return res, true
+		}
+		if rematch {
+			sp.rematches++
+		}
+		if sp.steps > swissMaxPairingSteps {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,192 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMakeSwiss(t *testing.T) {
+
+	teams := makeTeams(8)
+	swissFactory := SwissFactory{MaxRounds: 3, AvailableCourts: 4}
+
+	swiss, err := swissFactory.MakeTournament("swiss", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building swiss: %v", err)
+	}
+
+	t.Run("Assertion_1_TopHalfMeetsBottomHalf", func(t *testing.T) {
+		for i, m := range swiss.Rounds[0].Matches {
+			if *m.TeamA != teams[i] || *m.TeamB != teams[i+4] {
+				t.Errorf("expected seed %d against seed %d, got %v vs %v", i+1, i+5, *m.TeamA, *m.TeamB)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_NextRoundNeedsResults", func(t *testing.T) {
+		if _, err := swissFactory.MakeNextRound(swiss); err == nil {
+			t.Errorf("expected an error when the first round has no results")
+		}
+	})
+
+	for len(swiss.Rounds) < 3 {
+		completeBySeed(&swiss.Rounds[len(swiss.Rounds)-1], teams)
+		round, err := swissFactory.MakeNextRound(swiss)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building next round: %v", err)
+		}
+		swiss.Rounds = append(swiss.Rounds, round)
+	}
+
+	t.Run("Assertion_3_WinnersMeetWinners", func(t *testing.T) {
+		winners := map[Team]bool{teams[0]: true, teams[1]: true, teams[2]: true, teams[3]: true}
+		for _, m := range swiss.Rounds[1].Matches {
+			if winners[*m.TeamA] != winners[*m.TeamB] {
+				t.Errorf("expected teams with the same wins to meet, got %v vs %v", *m.TeamA, *m.TeamB)
+			}
+		}
+	})
+
+	t.Run("Assertion_4_NoRematches", func(t *testing.T) {
+		played := make(map[[2]Team]bool)
+		for _, round := range swiss.Rounds {
+			for _, m := range round.Matches {
+				if played[[2]Team{*m.TeamA, *m.TeamB}] {
+					t.Errorf("%v and %v met twice", *m.TeamA, *m.TeamB)
+				}
+				played[[2]Team{*m.TeamA, *m.TeamB}] = true
+				played[[2]Team{*m.TeamB, *m.TeamA}] = true
+			}
+		}
+	})
+
+	t.Run("Assertion_5_NoMoreRounds", func(t *testing.T) {
+		completeBySeed(&swiss.Rounds[2], teams)
+		if _, err := swissFactory.MakeNextRound(swiss); err == nil {
+			t.Errorf("expected an error once all rounds have been played")
+		}
+	})
+}
+
+func TestMakeSwissOddTeams(t *testing.T) {
+
+	teams := makeTeams(5)
+	swissFactory := SwissFactory{AvailableCourts: 2}
+
+	swiss, err := swissFactory.MakeTournament("swiss", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building swiss: %v", err)
+	}
+
+	for len(swiss.Rounds) < 5 {
+		completeBySeed(&swiss.Rounds[len(swiss.Rounds)-1], teams)
+		round, err := swissFactory.MakeNextRound(swiss)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building round %d: %v", len(swiss.Rounds)+1, err)
+		}
+		swiss.Rounds = append(swiss.Rounds, round)
+	}
+
+	t.Run("Assertion_1_EveryTeamRestsOnce", func(t *testing.T) {
+		byes := make(map[string]int)
+		for i := range swiss.Rounds {
+			for _, r := range swiss.GetResting(i, "-") {
+				byes[r]++
+			}
+		}
+		if len(byes) != 5 {
+			t.Errorf("expected every team to rest once, got %v", byes)
+		}
+	})
+
+	t.Run("Assertion_2_RematchesOnceEveryPairHasMet", func(t *testing.T) {
+		completeBySeed(&swiss.Rounds[4], teams)
+		round, err := swissFactory.MakeNextRound(swiss)
+		if err != nil {
+			t.Fatalf("expected rematches once every pair has met, got %v", err)
+		}
+		if len(round.Matches) != 2 {
+			t.Errorf("expected 2 matches, got %d", len(round.Matches))
+		}
+	})
+}
+
+func TestSwissPairerFewestRematches(t *testing.T) {
+	played := MakeGraph()
+	for _, e := range []edge{{0, 1}, {0, 2}, {0, 3}} {
+		played.AddEdge(e)
+	}
+	pairer := swissPairer{played: played, wins: map[int]int{}}
+
+	pairs, err := pairer.pair([]int{0, 1, 2, 3})
+	if err != nil {
+		t.Fatalf("expected a pairing with rematches, got %v", err)
+	}
+	rematches := 0
+	for _, p := range pairs {
+		if played.HasEdge(p) {
+			rematches++
+		}
+	}
+	if rematches != 1 {
+		t.Errorf("expected a single rematch, got %d in %v", rematches, pairs)
+	}
+}
+
+func TestMakeSwissWithFewerCourtsThanMatches(t *testing.T) {
+
+	teams := makeTeams(8)
+	swissFactory := SwissFactory{MaxRounds: 3, AvailableCourts: 2}
+
+	swiss, err := swissFactory.MakeTournament("swiss", teams, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error encountered while building swiss: %v", err)
+	}
+
+	for {
+		completeBySeed(&swiss.Rounds[len(swiss.Rounds)-1], teams)
+		round, err := swissFactory.MakeNextRound(swiss)
+		if err != nil {
+			break
+		}
+		swiss.Rounds = append(swiss.Rounds, round)
+	}
+
+	t.Run("Assertion_1_SwissRoundsAreSplit", func(t *testing.T) {
+		if len(swiss.Rounds) != 6 {
+			t.Fatalf("expected 3 swiss rounds over 6 rounds, got %d", len(swiss.Rounds))
+		}
+		for i, round := range swiss.Rounds {
+			courts := make(map[int]bool)
+			for _, m := range round.Matches {
+				if courts[m.CourtId] {
+					t.Errorf("court %d is used twice in round %d", m.CourtId, i+1)
+				}
+				courts[m.CourtId] = true
+			}
+			if len(round.Matches) != 2 {
+				t.Errorf("expected 2 matches in round %d, got %d", i+1, len(round.Matches))
+			}
+		}
+	})
+
+	t.Run("Assertion_2_EveryTeamPlaysOncePerSwissRound", func(t *testing.T) {
+		for r := 0; r < len(swiss.Rounds); r += 2 {
+			playing := make(map[Team]int)
+			for _, round := range swiss.Rounds[r : r+2] {
+				for _, m := range round.Matches {
+					playing[*m.TeamA]++
+					playing[*m.TeamB]++
+				}
+			}
+			for team, n := range playing {
+				if n != 1 {
+					t.Errorf("expected %v to play once in swiss round %d, got %d", team, r/2+1, n)
+				}
+			}
+			if len(playing) != 8 {
+				t.Errorf("expected every team to play in swiss round %d, got %d", r/2+1, len(playing))
+			}
+		}
+	})
+}
@@ -1,5 +1,7 @@
 package tournament
 
+import "encoding/json"
+
 type Gender int
 
 const (
@@ -8,6 +10,10 @@ const (
 	Else
 )
 
+// Unknown is the gender of a person decoded without one. It is not a gender
+// of teams, and people of unknown gender cannot be paired by gender.
+const Unknown Gender = -1
+
 func GetAllGenders() []Gender {
 	return []Gender{Male, Female, Else}
 }
@@ -23,20 +29,60 @@ func GenderFromString(g string) Gender {
 	}
 }
 
+// Person is identified by Id. Rating is the optional level of the player,
+// higher is stronger, 0 when it is not known; it is only used to generate
+// balanced matches. Gender is only used to pair people in single player
+// tournaments.
 type Person struct {
-	Id string `json:"id"`
+	Id     string  `json:"id"`
+	Rating float64 `json:"rating,omitempty"`
+	Gender Gender  `json:"gender"`
+}
+
+// UnmarshalJSON decodes a person, whose gender is Unknown when it is not
+// given, rather than Male.
+func (p *Person) UnmarshalJSON(data []byte) error {
+	type person Person
+	res := person{Gender: Unknown}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	*p = Person(res)
+	return nil
 }
 
 func (p Person) IsNil() bool {
 	return p.Id == ""
 }
 
+// The gender of a team of two people: theirs when they share it, Else, that
+// stands for mixed teams, otherwise or when a gender is Unknown.
+func PairGender(person1, person2 Person) Gender {
+	if person1.Gender == person2.Gender && person1.Gender != Unknown {
+		return person1.Gender
+	}
+	return Else
+}
+
 type Team struct {
 	Person1    Person `json:"person1"`
 	Person2    Person `json:"person2"`
 	TeamGender Gender `json:"gender"`
 }
 
+// TeamKey identifies a team by the Ids of its people and its gender, so that
+// a team compares equal whatever the ratings of its people, e.g. once stored
+// and read back.
+type TeamKey struct {
+	Person1    string
+	Person2    string
+	TeamGender Gender
+}
+
+func (t Team) Key() TeamKey {
+	return TeamKey{Person1: t.Person1.Id, Person2: t.Person2.Id, TeamGender: t.TeamGender}
+}
+
 func NewTeam(person1, person2 Person, teamGender Gender) *Team {
 	return &Team{
 		Person1:    person1,
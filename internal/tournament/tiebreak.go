@@ -0,0 +1,217 @@
+package tournament
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// A team, or a single player in a SinglePlayerRodeo, with the points scored
+// in the rodeo.
+type tieBreakEntry struct {
+	Team   Team
+	Points int
+}
+
+// TieBreakFactory generates the playoff of a rodeo whose final standings have
+// ties. Positions are the 1-based positions of the standings that must be
+// decided, e.g. 1, 2 and 3 for the podium; only the first position is
+// considered when it is empty.
+type TieBreakFactory struct {
+	AvailableCourts int
+	Positions       []int
+	// CourtWeights weights the courts when matches are rotated over them.
+	CourtWeights CourtWeights
+}
+
+func NewTieBreakFactory(availableCourts int, positions []int) *TieBreakFactory {
+	return &TieBreakFactory{
+		AvailableCourts: availableCourts,
+		Positions:       positions,
+	}
+}
+
+// GetRodeoStandings ranks the teams by the points they scored in the rodeo
+// matches, the team with most points wins. Tie-break matches are not
+// counted, ties keep the order of teams.
+func GetRodeoStandings(teams []Team, rounds []Round) []TeamStanding {
+	standings := GetTeamStandings(teams, stageRounds(rounds, StageMain))
+	sort.SliceStable(standings, func(i, j int) bool {
+		return standings[i].PointsFor > standings[j].PointsFor
+	})
+	return standings
+}
+
+// Returns the standings of a rodeo, after checking that every rodeo match has
+// been completed and that the playoff has not been generated yet.
+func tieBreakStandings(t Tournament) ([]tieBreakEntry, error) {
+	rounds := t.GetRounds()
+	if len(stageRounds(rounds, StageTieBreak)) > 0 {
+		return nil, errors.New("the tie-break has already been generated")
+	}
+	for _, r := range rounds {
+		for _, m := range r.Matches {
+			if m.MatchStatus != MatchCompleted {
+				return nil, errors.New("the tournament is not over: some matches have no result yet")
+			}
+		}
+	}
+
+	var entries []tieBreakEntry
+	switch t := t.(type) {
+	case *Rodeo:
+		for _, s := range GetRodeoStandings(t.Teams, t.Rounds) {
+			entries = append(entries, tieBreakEntry{Team: s.Team, Points: s.PointsFor})
+		}
+	case *SinglePlayerRodeo:
+		// Players are ranked on their own, so the tie-break is played
+		// between single players, each with a partner that is not tied.
+		for _, s := range GetPlayerStandings(GetPeople(t.Teams), stageRounds(t.Rounds, StageMain)) {
+			entries = append(entries, tieBreakEntry{Team: Team{Person1: s.Player}, Points: s.Points})
+		}
+	default:
+		return nil, fmt.Errorf("tournament %s does not support tie-breaks", t.GetName())
+	}
+
+	return entries, nil
+}
+
+// Gives a partner to the tied players of a single player rodeo, so that the
+// tie-break is played in pairs like the rodeo. Partners are the players that
+// are not tied, in turn; they never play twice in the same round.
+func partnerTiedPlayers(rounds []Round, entries []tieBreakEntry, ties [][]tieBreakEntry) error {
+	tied := make(map[string]bool)
+	for _, tie := range ties {
+		for _, e := range tie {
+			tied[e.Team.Person1.Id] = true
+		}
+	}
+	var partners []Person
+	for _, e := range entries {
+		if !tied[e.Team.Person1.Id] {
+			partners = append(partners, e.Team.Person1)
+		}
+	}
+
+	next := 0
+	for r := range rounds {
+		if 2*len(rounds[r].Matches) > len(partners) {
+			return fmt.Errorf(
+				"%d tie-break matches need %d partners, but only %d players are not tied",
+				len(rounds[r].Matches),
+				2*len(rounds[r].Matches),
+				len(partners),
+			)
+		}
+		for i := range rounds[r].Matches {
+			m := &rounds[r].Matches[i]
+			teamA := makePairTeam(m.TeamA.Person1, partners[next%len(partners)])
+			teamB := makePairTeam(m.TeamB.Person1, partners[(next+1)%len(partners)])
+			m.TeamA, m.TeamB = &teamA, &teamB
+			next += 2
+		}
+	}
+	return nil
+}
+
+// Returns the groups of entries tied for the given positions. A group that
+// covers more positions is returned once.
+func findTies(entries []tieBreakEntry, positions []int) ([][]tieBreakEntry, error) {
+	var ties [][]tieBreakEntry
+	var covered []int
+
+	for _, position := range positions {
+		if position < 1 || position > len(entries) {
+			return nil, fmt.Errorf("position %d is not in the standings", position)
+		}
+		if slices.Contains(covered, position) {
+			continue
+		}
+
+		points := entries[position-1].Points
+		first, last := position-1, position-1
+		for first > 0 && entries[first-1].Points == points {
+			first--
+		}
+		for last < len(entries)-1 && entries[last+1].Points == points {
+			last++
+		}
+
+		for p := first + 1; p <= last+1; p++ {
+			covered = append(covered, p)
+		}
+		if last > first {
+			ties = append(ties, entries[first:last+1])
+		}
+	}
+
+	return ties, nil
+}
+
+// MakeTieBreak returns the playoff rounds that decide the ties for the
+// configured positions of a completed Rodeo or SinglePlayerRodeo. Two tied
+// teams play a final, more teams play a round robin. The playoffs of
+// different positions are played side by side on the available courts.
+func (tf *TieBreakFactory) MakeTieBreak(t Tournament) ([]Round, error) {
+	if tf.AvailableCourts <= 0 {
+		return nil, errors.New("at least one court is needed to generate a round")
+	}
+
+	entries, err := tieBreakStandings(t)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := tf.Positions
+	if len(positions) == 0 {
+		positions = []int{1}
+	}
+
+	ties, err := findTies(entries, positions)
+	if err != nil {
+		return nil, err
+	}
+	if len(ties) == 0 {
+		return nil, errors.New("there are no ties to break at the given positions")
+	}
+
+	// The k-th round of every playoff is played at the same time.
+	var playoffRounds [][]Match
+	for _, tie := range ties {
+		for k, round := range circleMethod(len(tie)) {
+			if k == len(playoffRounds) {
+				playoffRounds = append(playoffRounds, nil)
+			}
+			for _, e := range round {
+				playoffRounds[k] = append(playoffRounds[k], Match{
+					TeamA: &tie[e.P1].Team,
+					TeamB: &tie[e.P2].Team,
+					Stage: StageTieBreak,
+				})
+			}
+		}
+	}
+
+	// Rounds with more matches than courts are split.
+	var rounds []Round
+	for _, matches := range playoffRounds {
+		for start := 0; start < len(matches); start += tf.AvailableCourts {
+			chunk := matches[start:min(start+tf.AvailableCourts, len(matches))]
+			for i := range chunk {
+				chunk[i].CourtId = i + 1
+			}
+			rounds = append(rounds, Round{Matches: chunk})
+		}
+	}
+	if _, ok := t.(*SinglePlayerRodeo); ok {
+		if err := partnerTiedPlayers(rounds, entries, ties); err != nil {
+			return nil, err
+		}
+	}
+
+	history := append(append([]Round{}, t.GetRounds()...), rounds...)
+	balanceCourts(history, len(t.GetRounds()), tf.CourtWeights)
+
+	return rounds, nil
+}
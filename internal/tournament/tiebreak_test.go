@@ -0,0 +1,137 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+)
+
+// Returns a completed rodeo of one round, where the teams i and i+1 play
+// each other with the given scores.
+func makeCompletedRodeo(teams []Team, scores [][2]int) *Rodeo {
+	matches := make([]Match, len(scores))
+	for i, s := range scores {
+		matches[i] = Match{
+			TeamA:       &teams[2*i],
+			TeamB:       &teams[2*i+1],
+			CourtId:     i + 1,
+			MatchStatus: MatchCompleted,
+			ScoreA:      s[0],
+			ScoreB:      s[1],
+		}
+	}
+	return NewRodeo("rodeo", time.Now(), teams, []Round{{Matches: matches}})
+}
+
+func TestMakeTieBreak(t *testing.T) {
+
+	teams := makeTeams(6)
+
+	t.Run("Assertion_1_TwoTeamsPlayAFinal", func(t *testing.T) {
+		// Teams 0 and 2 are tied first, teams 1 and 3 fourth.
+		rodeo := makeCompletedRodeo(teams, [][2]int{{3, 1}, {3, 1}, {2, 0}})
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 2, Positions: []int{1, 2, 4}}
+
+		rounds, err := tieBreakFactory.MakeTieBreak(rodeo)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building tie-break: %v", err)
+		}
+		if len(rounds) != 1 || len(rounds[0].Matches) != 2 {
+			t.Fatalf("expected one round with two finals, got %v", rounds)
+		}
+		expected := [][2]Team{{teams[0], teams[2]}, {teams[1], teams[3]}}
+		for i, m := range rounds[0].Matches {
+			if *m.TeamA != expected[i][0] || *m.TeamB != expected[i][1] {
+				t.Errorf("expected %v, got %v vs %v", expected[i], *m.TeamA, *m.TeamB)
+			}
+			if m.Stage != StageTieBreak || m.CourtId != i+1 {
+				t.Errorf("expected a tie-break match on court %d, got %v on court %d", i+1, m.Stage, m.CourtId)
+			}
+		}
+	})
+
+	t.Run("Assertion_2_MoreTeamsPlayARoundRobin", func(t *testing.T) {
+		// Teams 0 to 3 are tied first.
+		rodeo := makeCompletedRodeo(teams, [][2]int{{3, 3}, {3, 3}, {1, 0}})
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 1}
+
+		rounds, err := tieBreakFactory.MakeTieBreak(rodeo)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building tie-break: %v", err)
+		}
+		if len(rounds) != 6 {
+			t.Fatalf("expected 6 rounds of one match, got %d rounds", len(rounds))
+		}
+
+		played := MakeGraph()
+		index := make(map[Team]int)
+		for i, team := range teams {
+			index[team] = i
+		}
+		for _, r := range rounds {
+			if len(r.Matches) != 1 {
+				t.Fatalf("expected one match per round, got %d", len(r.Matches))
+			}
+			m := r.Matches[0]
+			a, b := index[*m.TeamA], index[*m.TeamB]
+			if a > 3 || b > 3 {
+				t.Errorf("team not in the tie playing the tie-break: %v vs %v", *m.TeamA, *m.TeamB)
+			}
+			played.AddEdge(edge{Node(a), Node(b)})
+		}
+		if played.Size() != 6 {
+			t.Errorf("expected every tied pair to meet once, got %d pairs", played.Size())
+		}
+	})
+
+	t.Run("Assertion_3_NoTies", func(t *testing.T) {
+		rodeo := makeCompletedRodeo(teams, [][2]int{{4, 1}, {3, 1}, {2, 0}})
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 2, Positions: []int{1, 2}}
+		if _, err := tieBreakFactory.MakeTieBreak(rodeo); err == nil {
+			t.Errorf("expected an error when there are no ties")
+		}
+	})
+
+	t.Run("Assertion_4_TournamentNotOver", func(t *testing.T) {
+		rodeo := makeCompletedRodeo(teams, [][2]int{{3, 1}, {3, 1}, {2, 0}})
+		rodeo.Rounds[0].Matches[2].MatchStatus = MatchScheduled
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 2}
+		if _, err := tieBreakFactory.MakeTieBreak(rodeo); err == nil {
+			t.Errorf("expected an error when some matches have no result")
+		}
+	})
+
+	t.Run("Assertion_5_SinglePlayersTieBreak", func(t *testing.T) {
+		rodeo := makeCompletedRodeo(teams, [][2]int{{3, 1}, {2, 1}, {2, 0}})
+		spRodeo := NewSinglePlayerRodeo("rodeo", time.Now(), rodeo.Teams, rodeo.Rounds)
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 2}
+
+		rounds, err := tieBreakFactory.MakeTieBreak(spRodeo)
+		if err != nil {
+			t.Fatalf("unexpected error encountered while building tie-break: %v", err)
+		}
+		m := rounds[0].Matches[0]
+		if m.TeamA.Person1 != teams[0].Person1 || m.TeamB.Person1 != teams[0].Person2 {
+			t.Errorf("expected the players of team 0 to play the final, got %v vs %v", *m.TeamA, *m.TeamB)
+		}
+		partners := []Person{m.TeamA.Person2, m.TeamB.Person2}
+		for _, p := range partners {
+			if p.Id == "" || p == teams[0].Person1 || p == teams[0].Person2 {
+				t.Errorf("expected the tied players to have partners that are not tied, got %v", partners)
+			}
+		}
+		if partners[0] == partners[1] {
+			t.Errorf("expected different partners, got %v", partners)
+		}
+	})
+
+	t.Run("Assertion_6_SinglePlayersWithoutPartners", func(t *testing.T) {
+		// All 4 players are tied, nobody is left to partner them.
+		rodeo := makeCompletedRodeo(teams[:2], [][2]int{{2, 2}})
+		spRodeo := NewSinglePlayerRodeo("rodeo", time.Now(), rodeo.Teams, rodeo.Rounds)
+		tieBreakFactory := TieBreakFactory{AvailableCourts: 2}
+
+		if _, err := tieBreakFactory.MakeTieBreak(spRodeo); err == nil {
+			t.Errorf("expected an error when no player can partner the tied ones")
+		}
+	})
+}
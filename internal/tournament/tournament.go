@@ -5,8 +5,13 @@ import (
 	"time"
 )
 
+// Round is a set of matches played at the same time. Date is when the round
+// starts: the day of the round for formats spanning more than one day, or its
+// start time for rodeos given a match duration. It is the zero time
+// otherwise.
 type Round struct {
-	Matches []Match `json:"matches"`
+	Matches []Match   `json:"matches"`
+	Date    time.Time `json:"date"`
 }
 
 type MatchStatus int
@@ -17,19 +22,79 @@ const (
 	MatchCompleted
 )
 
+// MatchStage tells which part of a tournament a match belongs to, for formats
+// that are played in more than one stage.
+type MatchStage int
+
+const (
+	StageMain MatchStage = iota
+	StageGroup
+	StageConsolation
+	StageThirdPlace
+	StageTieBreak
+)
+
+// IsEliminationStage tells whether the matches of the stage decide which
+// team goes on in the brackets of the tournament type, so that they cannot
+// end in a draw.
+func IsEliminationStage(tournamentType TournamentType, stage MatchStage) bool {
+	switch tournamentType {
+	case TournamentTypeKnockout, TournamentTypeGroupKnockout:
+		return stage == StageMain || stage == StageConsolation || stage == StageThirdPlace
+	default:
+		return false
+	}
+}
+
 type TournamentType int
 
 const (
 	TournamentTypeEmpty TournamentType = iota
 	TournamentTypeRodeo
 	TournamentTypeSinglePlayerRodeo
+	TournamentTypeAmericano
+	TournamentTypeMexicano
+	TournamentTypeKnockout
+	TournamentTypeGroupKnockout
+	TournamentTypeRoundRobin
+	TournamentTypeSwiss
+	TournamentTypeKingOfTheCourt
+	TournamentTypeInterclub
 )
 
 type Match struct {
+	Id          int64       `json:"id"`
 	TeamA       *Team       `json:"teamA"`
 	TeamB       *Team       `json:"teamB"`
 	MatchStatus MatchStatus `json:"matchStatus"`
 	CourtId     int         `json:"courtId"`
+	ScoreA      int         `json:"scoreA"`
+	ScoreB      int         `json:"scoreB"`
+	Stage       MatchStage  `json:"stage"`
+}
+
+// Winner returns the team that won a completed match, nil when the match is
+// not completed yet or ended in a draw.
+func (m Match) Winner() *Team {
+	if m.MatchStatus != MatchCompleted || m.ScoreA == m.ScoreB {
+		return nil
+	}
+	if m.ScoreA > m.ScoreB {
+		return m.TeamA
+	}
+	return m.TeamB
+}
+
+// Loser returns the team that lost a completed match, nil when the match is
+// not completed yet or ended in a draw.
+func (m Match) Loser() *Team {
+	if m.MatchStatus != MatchCompleted || m.ScoreA == m.ScoreB {
+		return nil
+	}
+	if m.ScoreA > m.ScoreB {
+		return m.TeamB
+	}
+	return m.TeamA
 }
 
 type Tournament interface {
@@ -42,23 +107,32 @@ type Tournament interface {
 }
 
 type TournamentData struct {
-	Name           string         `json:"name"`
-	Date           time.Time      `json:"date"`
-	Teams          []Team         `json:"teams"`
-	Rounds         []Round        `json:"rounds"`
-	TournamentType TournamentType `json:"tournamentType"`
+	Id             int64              `json:"id"`
+	Name           string             `json:"name"`
+	Date           time.Time          `json:"date"`
+	Teams          []Team             `json:"teams"`
+	Rounds         []Round            `json:"rounds"`
+	TournamentType TournamentType     `json:"tournamentType"`
+	Groups         [][]Team           `json:"groups,omitempty"`
+	Qualifiers     int                `json:"qualifiers,omitempty"`
+	Elimination    EliminationOptions `json:"elimination"`
+	Clubs          []Club             `json:"clubs,omitempty"`
+	// RodeoOptions are the options rodeos were made with.
+	RodeoOptions RodeoOptions `json:"-"`
 }
 
 func (t TournamentData) ToTournament() Tournament {
 
 	switch t.TournamentType {
 	case TournamentTypeRodeo:
-		return NewRodeo(
+		rodeo := NewRodeo(
 			t.Name,
 			t.Date,
 			t.Teams,
 			t.Rounds,
 		)
+		rodeo.Options = t.RodeoOptions
+		return rodeo
 	case TournamentTypeSinglePlayerRodeo:
 		return NewSinglePlayerRodeo(
 			t.Name,
@@ -66,6 +140,67 @@ func (t TournamentData) ToTournament() Tournament {
 			t.Teams,
 			t.Rounds,
 		)
+	case TournamentTypeAmericano:
+		return NewAmericano(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+	case TournamentTypeMexicano:
+		return NewMexicano(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+	case TournamentTypeKnockout:
+		knockout := NewKnockout(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+		knockout.Elimination = t.Elimination
+		return knockout
+	case TournamentTypeGroupKnockout:
+		groupKnockout := NewGroupKnockout(
+			t.Name,
+			t.Date,
+			t.Groups,
+			t.Qualifiers,
+			t.Rounds,
+		)
+		groupKnockout.Elimination = t.Elimination
+		return groupKnockout
+	case TournamentTypeRoundRobin:
+		return NewRoundRobin(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+	case TournamentTypeSwiss:
+		return NewSwiss(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+	case TournamentTypeKingOfTheCourt:
+		return NewKingOfTheCourt(
+			t.Name,
+			t.Date,
+			t.Teams,
+			t.Rounds,
+		)
+	case TournamentTypeInterclub:
+		return NewInterclub(
+			t.Name,
+			t.Date,
+			t.Clubs,
+			t.Rounds,
+		)
 	default:
 		return nil
 	}
@@ -79,7 +214,11 @@ func MakeTournamentData(
 	tournamentType TournamentType,
 ) TournamentData {
 	return TournamentData{
-		name, date, teams, rounds, tournamentType,
+		Name:           name,
+		Date:           date,
+		Teams:          teams,
+		Rounds:         rounds,
+		TournamentType: tournamentType,
 	}
 }
 
@@ -89,6 +228,22 @@ func TournamentTypeToString(t TournamentType) (string, error) {
 		return "Rodeo", nil
 	case TournamentTypeSinglePlayerRodeo:
 		return "SinglePlayerRodeo", nil
+	case TournamentTypeAmericano:
+		return "Americano", nil
+	case TournamentTypeMexicano:
+		return "Mexicano", nil
+	case TournamentTypeKnockout:
+		return "Knockout", nil
+	case TournamentTypeGroupKnockout:
+		return "GroupKnockout", nil
+	case TournamentTypeRoundRobin:
+		return "RoundRobin", nil
+	case TournamentTypeSwiss:
+		return "Swiss", nil
+	case TournamentTypeKingOfTheCourt:
+		return "KingOfTheCourt", nil
+	case TournamentTypeInterclub:
+		return "Interclub", nil
 	default:
 		return "", fmt.Errorf("invalid tournament type: %d", t)
 	}
@@ -100,6 +255,22 @@ func TournamentTypeFromString(t string) (TournamentType, error) {
 		return TournamentTypeRodeo, nil
 	case "SinglePlayerRodeo":
 		return TournamentTypeSinglePlayerRodeo, nil
+	case "Americano":
+		return TournamentTypeAmericano, nil
+	case "Mexicano":
+		return TournamentTypeMexicano, nil
+	case "Knockout":
+		return TournamentTypeKnockout, nil
+	case "GroupKnockout":
+		return TournamentTypeGroupKnockout, nil
+	case "RoundRobin":
+		return TournamentTypeRoundRobin, nil
+	case "Swiss":
+		return TournamentTypeSwiss, nil
+	case "KingOfTheCourt":
+		return TournamentTypeKingOfTheCourt, nil
+	case "Interclub":
+		return TournamentTypeInterclub, nil
 	default:
 		return TournamentTypeEmpty, fmt.Errorf("invalid tournament type: %s", t)
 	}
@@ -1,5 +1,13 @@
 BEGIN;
 
+CREATE TABLE IF NOT EXISTS club
+(
+    id serial NOT NULL,
+    name character varying(255) NOT NULL,
+    CONSTRAINT club_pkey PRIMARY KEY (id),
+    CONSTRAINT club_name_key UNIQUE (name)
+);
+
 CREATE TABLE IF NOT EXISTS gender
 (
     id serial NOT NULL,
@@ -8,6 +16,37 @@ CREATE TABLE IF NOT EXISTS gender
     CONSTRAINT gender_name_key UNIQUE (name)
 );
 
+CREATE TABLE IF NOT EXISTS ladder
+(
+    id serial NOT NULL,
+    event_name character varying(255) COLLATE pg_catalog."default",
+    start_date timestamp without time zone NOT NULL,
+    max_challenge_distance integer NOT NULL DEFAULT 1,
+    user_id bigint NOT NULL,
+    CONSTRAINT ladder_pkey PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS ladder_team
+(
+    ladder_id integer NOT NULL,
+    team_id integer NOT NULL,
+    rank integer NOT NULL,
+    CONSTRAINT ladder_team_pkey PRIMARY KEY (ladder_id, team_id)
+);
+
+CREATE TABLE IF NOT EXISTS challenge
+(
+    id serial NOT NULL,
+    ladder_id integer NOT NULL,
+    challenger_id integer NOT NULL,
+    challenged_id integer NOT NULL,
+    status integer NOT NULL DEFAULT 0,
+    challenger_score integer NOT NULL DEFAULT 0,
+    challenged_score integer NOT NULL DEFAULT 0,
+    issued_at timestamp without time zone NOT NULL,
+    CONSTRAINT challenge_pkey PRIMARY KEY (id)
+);
+
 CREATE TABLE IF NOT EXISTS logos
 (
     id serial NOT NULL,
@@ -22,6 +61,10 @@ CREATE TABLE IF NOT EXISTS match
     team1_id integer,
     team2_id integer,
     court_number integer,
+    team1_score integer NOT NULL DEFAULT 0,
+    team2_score integer NOT NULL DEFAULT 0,
+    match_status integer NOT NULL DEFAULT 0,
+    stage integer NOT NULL DEFAULT 0,
     CONSTRAINT match_pkey PRIMARY KEY (id)
 );
 
@@ -38,6 +81,7 @@ CREATE TABLE IF NOT EXISTS round_tournament
     tournament_id integer NOT NULL,
     match_id integer NOT NULL,
     round_number integer NOT NULL,
+    round_date timestamp without time zone,
     CONSTRAINT round_tournament_pkey PRIMARY KEY (tournament_id, match_id)
 );
 
@@ -65,9 +109,23 @@ CREATE TABLE IF NOT EXISTS tournament
     tournament_date timestamp without time zone NOT NULL,
     tournament_type_id integer,
     user_id bigint NOT NULL,
+    qualifiers integer NOT NULL DEFAULT 0,
+    consolation boolean NOT NULL DEFAULT false,
+    third_place boolean NOT NULL DEFAULT false,
+    rodeo_options jsonb,
     CONSTRAINT tournament_pkey PRIMARY KEY (id)
 );
 
+CREATE TABLE IF NOT EXISTS tournament_team
+(
+    tournament_id integer NOT NULL,
+    team_id integer NOT NULL,
+    seed integer NOT NULL DEFAULT 0,
+    group_number integer NOT NULL DEFAULT 0,
+    club_id integer,
+    CONSTRAINT tournament_team_pkey PRIMARY KEY (tournament_id, team_id)
+);
+
 CREATE TABLE IF NOT EXISTS tournament_type
 (
     id serial NOT NULL,
@@ -84,6 +142,49 @@ CREATE TABLE IF NOT EXISTS users
     CONSTRAINT users_pkey PRIMARY KEY (id)
 );
 
+ALTER TABLE IF EXISTS challenge
+    ADD CONSTRAINT challenge_ladder_id_fkey FOREIGN KEY (ladder_id)
+    REFERENCES ladder (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS challenge
+    ADD CONSTRAINT challenge_challenger_id_fkey FOREIGN KEY (challenger_id)
+    REFERENCES team (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS challenge
+    ADD CONSTRAINT challenge_challenged_id_fkey FOREIGN KEY (challenged_id)
+    REFERENCES team (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS ladder
+    ADD CONSTRAINT ladder_user_id_fkey FOREIGN KEY (user_id)
+    REFERENCES users (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION
+    NOT VALID;
+
+
+ALTER TABLE IF EXISTS ladder_team
+    ADD CONSTRAINT ladder_team_ladder_id_fkey FOREIGN KEY (ladder_id)
+    REFERENCES ladder (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS ladder_team
+    ADD CONSTRAINT ladder_team_team_id_fkey FOREIGN KEY (team_id)
+    REFERENCES team (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
 ALTER TABLE IF EXISTS match
     ADD CONSTRAINT match_team1_id_fkey FOREIGN KEY (team1_id)
     REFERENCES team (id) MATCH SIMPLE
@@ -155,6 +256,27 @@ ALTER TABLE IF EXISTS tournament
     NOT VALID;
 
 
+ALTER TABLE IF EXISTS tournament_team
+    ADD CONSTRAINT tournament_team_tournament_id_fkey FOREIGN KEY (tournament_id)
+    REFERENCES tournament (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS tournament_team
+    ADD CONSTRAINT tournament_team_team_id_fkey FOREIGN KEY (team_id)
+    REFERENCES team (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
+ALTER TABLE IF EXISTS tournament_team
+    ADD CONSTRAINT tournament_team_club_id_fkey FOREIGN KEY (club_id)
+    REFERENCES club (id) MATCH SIMPLE
+    ON UPDATE NO ACTION
+    ON DELETE NO ACTION;
+
+
 ALTER TABLE IF EXISTS users
     ADD CONSTRAINT users_sports_center_id_fkey FOREIGN KEY (sports_center_id)
     REFERENCES sports_center (id) MATCH SIMPLE
@@ -166,5 +288,14 @@ END;
 
 INSERT INTO gender (id, name) VALUES (1, 'Male');
 INSERT INTO gender (id, name) VALUES (2, 'Female');
+INSERT INTO gender (id, name) VALUES (3, 'Mixed');
 INSERT INTO tournament_type (id, name) VALUES (1, 'Rodeo');
 INSERT INTO tournament_type (id, name) VALUES (2, 'SinglePlayerRodeo');
+INSERT INTO tournament_type (id, name) VALUES (3, 'Americano');
+INSERT INTO tournament_type (id, name) VALUES (4, 'Mexicano');
+INSERT INTO tournament_type (id, name) VALUES (5, 'Knockout');
+INSERT INTO tournament_type (id, name) VALUES (6, 'GroupKnockout');
+INSERT INTO tournament_type (id, name) VALUES (7, 'RoundRobin');
+INSERT INTO tournament_type (id, name) VALUES (8, 'Swiss');
+INSERT INTO tournament_type (id, name) VALUES (9, 'KingOfTheCourt');
+INSERT INTO tournament_type (id, name) VALUES (10, 'Interclub');